@@ -0,0 +1,263 @@
+/*
+Copyright © 2023 yizhixiaokong
+*/
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"kongtools/internal/config"
+	"kongtools/internal/pkg/editor"
+	"kongtools/internal/task"
+	"kongtools/internal/ui"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var configOpts struct {
+	force bool
+	all   bool
+}
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage the config file",
+	Long: `Manage the config file.
+
+The config file is given by --config, or $XDG_CONFIG_HOME/kongtools/config.yaml by default,
+$XDG_CONFIG_HOME defaults to ~/.config. An old ~/.kongtoolsrc is moved there on the first run.
+Keys are case insensitive and separated by dots, e.g. log.level or app.todo.titleLimit.`,
+	// 配置无效时也要能查看和修改, 不加载配置
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		migrate(cmd)
+		return nil
+	},
+}
+
+var configInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Write the default config file",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, _ := config.Path()
+		if err := config.Init(path, configOpts.force); err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), "Created config file:", path)
+		return nil
+	},
+}
+
+var configPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Print the path of the config file",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, exists := config.Path()
+		if configOpts.all {
+			return printPaths(cmd, path)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), path)
+		switch {
+		case exists:
+		case config.CfgFile != "":
+			fmt.Fprintln(cmd.ErrOrStderr(), "The file does not exist. Create it with: kongtools config init --config", path)
+		default:
+			fmt.Fprintln(cmd.ErrOrStderr(), "The file does not exist, the default configuration is used. Create it with: kongtools config init")
+		}
+		return nil
+	},
+}
+
+// printPaths 打印配置文件和配置中的各个路径
+func printPaths(cmd *cobra.Command, path string) error {
+	if err := config.Load(); err != nil {
+		return err
+	}
+	cfg := config.Config()
+	session := cfg.App.Session.Path
+	if session == "" {
+		var err error
+		if session, err = ui.DefaultStatePath(); err != nil {
+			return err
+		}
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "config\t%s\n", path)
+	fmt.Fprintf(w, "tasks\t%s\n", cfg.App.TasksSavePath)
+	fmt.Fprintf(w, "journal\t%s\n", task.JournalPath(cfg.App.TasksSavePath))
+	fmt.Fprintf(w, "log\t%s\n", cfg.Log.Filename)
+	fmt.Fprintf(w, "themes\t%s\n", cfg.App.Theme.Dir)
+	fmt.Fprintf(w, "session\t%s\n", session)
+	return w.Flush()
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the configuration in use",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		settings, err := config.Settings()
+		if err != nil {
+			return err
+		}
+		return printYAML(cmd, settings)
+	},
+}
+
+var configGetCmd = &cobra.Command{
+	Use:     "get <key>",
+	Short:   "Print the value of a key",
+	Example: "  kongtools config get log.level\n  kongtools config get app.board",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		value, err := config.Get(args[0])
+		if err != nil {
+			return err
+		}
+		switch value.(type) {
+		case map[string]any, []any:
+			return printYAML(cmd, value)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), value)
+		return nil
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Set the value of a key in the config file",
+	Long: `Set the value of a key in the config file, keeping its comments.
+
+The value is parsed as YAML, lists are written as [a, b]. An empty value clears the key.
+The config file is created from the default configuration when it does not exist.
+Nothing is written when the new configuration is invalid.`,
+	Example: "  kongtools config set log.level info\n  kongtools config set app.tools \"[todo-list, board]\"",
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, _ := config.Path()
+		return config.Set(path, args[0], args[1])
+	},
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate [file]",
+	Short: "Check the config file for unknown keys and invalid values",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, _ := config.Path()
+		if len(args) > 0 {
+			path = args[0]
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if err := config.Validate(path, data); err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), "Config file is valid:", path)
+		return nil
+	},
+}
+
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Edit the config file in $VISUAL or $EDITOR",
+	Long: `Edit the config file in $VISUAL or $EDITOR, vi is used when neither is set.
+
+The changes are checked before they are saved, an invalid file can be edited again or discarded.`,
+	Args: cobra.NoArgs,
+	RunE: configEditRun,
+}
+
+func configEditRun(cmd *cobra.Command, args []string) error {
+	path, exists := config.Path()
+	original := []byte(config.Default())
+	if exists {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		original = data
+	}
+
+	file, err := os.CreateTemp("", "kongtools-config-*.yaml")
+	if err != nil {
+		return err
+	}
+	temp := file.Name()
+	file.Close()
+	defer os.Remove(temp)
+
+	data := original
+	in := bufio.NewReader(cmd.InOrStdin())
+	for {
+		if err := os.WriteFile(temp, data, 0o600); err != nil {
+			return err
+		}
+		if err := editor.Run(temp); err != nil {
+			return err
+		}
+		edited, err := os.ReadFile(temp)
+		if err != nil {
+			return err
+		}
+		if exists && bytes.Equal(edited, original) {
+			fmt.Fprintln(cmd.OutOrStdout(), "No changes.")
+			return nil
+		}
+
+		err = config.Validate(path, edited)
+		if err == nil {
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				return err
+			}
+			if err := os.WriteFile(path, edited, 0o644); err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "Saved config file:", path)
+			return nil
+		}
+
+		fmt.Fprintln(cmd.ErrOrStderr(), err)
+		fmt.Fprint(cmd.ErrOrStderr(), "Edit again? [Y/n] ")
+		answer, err := in.ReadString('\n')
+		if err != nil {
+			fmt.Fprintln(cmd.ErrOrStderr())
+		}
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		// 没有输入时(如stdin已关闭)不再编辑
+		if answer == "n" || answer == "no" || (answer == "" && err != nil) {
+			return errors.New("changes discarded")
+		}
+		data = edited
+	}
+}
+
+func printYAML(cmd *cobra.Command, value any) error {
+	enc := yaml.NewEncoder(cmd.OutOrStdout())
+	enc.SetIndent(2)
+	if err := enc.Encode(value); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configInitCmd, configPathCmd, configShowCmd, configGetCmd, configSetCmd, configValidateCmd, configEditCmd)
+
+	configInitCmd.Flags().BoolVarP(&configOpts.force, "force", "f", false, "overwrite an existing config file")
+	configPathCmd.Flags().BoolVarP(&configOpts.all, "all", "a", false, "also print the files of tasks, logs, themes and the session")
+}
@@ -0,0 +1,125 @@
+/*
+Copyright © 2023 yizhixiaokong
+*/
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"kongtools/internal/config"
+	"kongtools/internal/task"
+
+	"github.com/spf13/cobra"
+)
+
+var reportOpts struct {
+	since   string
+	until   string
+	groupBy string
+	format  string
+}
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Report tracked time of tasks",
+	Long: `Report tracked time of tasks.
+
+Dates accept "2006-01-02" or RFC3339, --until is exclusive.
+When grouping by tag, a task with several tags counts towards each of them.`,
+	Args: cobra.NoArgs,
+	RunE: reportRun,
+}
+
+func reportRun(cmd *cobra.Command, args []string) error {
+	since, err := parseReportTime(reportOpts.since)
+	if err != nil {
+		return fmt.Errorf("invalid --since: %w", err)
+	}
+	until, err := parseReportTime(reportOpts.until)
+	if err != nil {
+		return fmt.Errorf("invalid --until: %w", err)
+	}
+
+	tasks, err := task.Load(config.Config().App.TasksSavePath)
+	if err != nil {
+		return err
+	}
+
+	rows, total, err := task.Report(tasks, since, until, reportOpts.groupBy, time.Now())
+	if err != nil {
+		return err
+	}
+
+	w := cmd.OutOrStdout()
+	switch reportOpts.format {
+	case "table":
+		return writeReportTable(w, rows, total)
+	case "csv":
+		return writeReportCSV(w, rows)
+	case "json":
+		return writeReportJSON(w, rows)
+	default:
+		return fmt.Errorf("unknown format: %q", reportOpts.format)
+	}
+}
+
+func parseReportTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.ParseInLocation(time.DateOnly, s, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+func writeReportTable(w io.Writer, rows []task.ReportRow, total time.Duration) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "%s\tDURATION\tHOURS\n", strings.ToUpper(reportOpts.groupBy))
+	for _, row := range rows {
+		fmt.Fprintf(tw, "%s\t%s\t%.2f\n", row.Group, task.FormatDuration(row.Duration), row.Hours)
+	}
+	fmt.Fprintf(tw, "TOTAL\t%s\t%.2f\n", task.FormatDuration(total), total.Hours())
+	return tw.Flush()
+}
+
+func writeReportCSV(w io.Writer, rows []task.ReportRow) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{reportOpts.groupBy, "seconds", "hours"}); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		record := []string{
+			row.Group,
+			strconv.FormatInt(row.Seconds, 10),
+			strconv.FormatFloat(row.Hours, 'f', 2, 64),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeReportJSON(w io.Writer, rows []task.ReportRow) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(rows)
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+
+	reportCmd.Flags().StringVar(&reportOpts.since, "since", "", "only count time after this date")
+	reportCmd.Flags().StringVar(&reportOpts.until, "until", "", "only count time before this date")
+	reportCmd.Flags().StringVar(&reportOpts.groupBy, "group-by", task.GroupByTag, "group totals by tag, task or day")
+	reportCmd.Flags().StringVarP(&reportOpts.format, "format", "o", "table", "output format: table, csv or json")
+}
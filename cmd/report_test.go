@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"kongtools/internal/task"
+)
+
+var reportRows = []task.ReportRow{
+	{Group: "client, \"acme\"", Duration: 90 * time.Minute, Seconds: 5400, Hours: 1.5},
+	{Group: task.Untagged, Duration: 20 * time.Minute, Seconds: 1200, Hours: 1.0 / 3},
+}
+
+func TestWriteReportCSV(t *testing.T) {
+	reportOpts.groupBy = task.GroupByTag
+	var buf bytes.Buffer
+	if err := writeReportCSV(&buf, reportRows); err != nil {
+		t.Fatal(err)
+	}
+	want := "tag,seconds,hours\n" +
+		"\"client, \"\"acme\"\"\",5400,1.50\n" +
+		"(untagged),1200,0.33\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("csv =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestWriteReportJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeReportJSON(&buf, reportRows); err != nil {
+		t.Fatal(err)
+	}
+	var got []map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	want := []map[string]any{
+		{"group": "client, \"acme\"", "seconds": 5400.0, "hours": 1.5},
+		{"group": task.Untagged, "seconds": 1200.0, "hours": 1.0 / 3},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d rows, want %d: %s", len(got), len(want), buf.String())
+	}
+	for i := range want {
+		if len(got[i]) != len(want[i]) {
+			t.Errorf("row %d = %v, want %v", i, got[i], want[i])
+		}
+		for k, v := range want[i] {
+			if got[i][k] != v {
+				t.Errorf("row %d %s = %v, want %v", i, k, got[i][k], v)
+			}
+		}
+	}
+}
+
+func TestWriteReportTable(t *testing.T) {
+	reportOpts.groupBy = task.GroupByTag
+	var buf bytes.Buffer
+	if err := writeReportTable(&buf, reportRows, 110*time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	want := "TAG             DURATION  HOURS\n" +
+		"client, \"acme\"  1h30m     1.50\n" +
+		"(untagged)      20m       0.33\n" +
+		"TOTAL           1h50m     1.83\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("table =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestParseReportTime(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"", time.Time{}},
+		// 日期按本地时间的0点
+		{"2024-05-02", time.Date(2024, 5, 2, 0, 0, 0, 0, time.Local)},
+		{"2024-05-02T09:30:00Z", time.Date(2024, 5, 2, 9, 30, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := parseReportTime(tt.in)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("parseReportTime(%q) = %s, %v, want %s", tt.in, got, err, tt.want)
+		}
+	}
+	if _, err := parseReportTime("05/02"); err == nil {
+		t.Error("parseReportTime(\"05/02\") = nil error")
+	}
+}
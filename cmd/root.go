@@ -4,6 +4,7 @@ Copyright © 2023 yizhixiaokong
 package cmd
 
 import (
+	"fmt"
 	"kongtools/internal/config"
 	"kongtools/internal/view"
 	"os"
@@ -18,22 +19,59 @@ import (
 var rootCmd = &cobra.Command{
 	Use:   "kongtools",
 	Short: "kongtools is a command line tool for kong",
-	Long:  `kongtools is a command line tool for kong`,
-	Run:   rootRun,
+	Long:  "kongtools is a command line tool for kong\n\n" + config.Precedence,
+	RunE:  rootRun,
+	// 子命令运行前加载配置, config子命令有自己的PersistentPreRunE
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// 参数已解析, 之后的错误不显示用法
+		cmd.SilenceUsage = true
+		migrate(cmd)
+		if err := config.Load(); err != nil {
+			return err
+		}
+		log.InitLogger(config.Config().Log)
+		return nil
+	},
 }
 
-func rootRun(cmd *cobra.Command, args []string) {
+// migrate 把旧版本的配置文件和散落的任务、日志文件移到XDG目录, 失败时只提示
+func migrate(cmd *cobra.Command) {
+	moves, err := config.Migrate()
+	for _, m := range moves {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Moved %s to %s\n", m.From, m.To)
+	}
+	if err != nil {
+		fmt.Fprintln(cmd.ErrOrStderr(), "Cannot move files into the XDG directories:", err)
+	}
+}
+
+// startPage 启动时显示的页面, 优先于上次的页面和配置中的home
+var startPage string
+
+func rootRun(cmd *cobra.Command, args []string) error {
 	slog.Debug("run app start ...")
 	app := view.NewApp(slog.Default(), config.Config().App)
+	app.SetStartPage(startPage)
 	if err := app.Init(); err != nil {
 		slog.Error("init app error", slog.String("error", err.Error()))
-		return
+		return fmt.Errorf("init app: %w", err)
 	}
 
+	// 运行中修改配置文件时应用新的配置, 任何一项不能应用时都继续使用原来的配置
+	config.OnChange(func(logCfg log.Config, _ view.Config) error {
+		return log.SetLevel(logCfg.Level)
+	})
+	config.OnChange(func(_ log.Config, appCfg view.Config) error {
+		return app.Reload(appCfg)
+	})
+	config.OnError(app.ReloadError)
+	config.Watch()
+
 	if err := app.Run(); err != nil {
 		slog.Error("run app error", slog.String("error", err.Error()))
-		return
+		return fmt.Errorf("run app: %w", err)
 	}
+	return nil
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -46,10 +84,7 @@ func Execute() {
 }
 
 func init() {
-	cobra.OnInitialize(func() {
-		cfg := config.Config() // init config
-		log.InitLogger(cfg.Log)
-	})
-
-	rootCmd.PersistentFlags().StringVar(&config.CfgFile, "config", "", "config file (default is $HOME/.kongtoolsrc)")
+	rootCmd.PersistentFlags().StringVar(&config.CfgFile, "config", "", "config file (default is $XDG_CONFIG_HOME/kongtools/config.yaml)")
+	config.BindFlags(rootCmd.PersistentFlags())
+	rootCmd.Flags().StringVar(&startPage, "page", "", "page to start on, with optional params, e.g. todo-list or todo-list?due=2024-05-01")
 }
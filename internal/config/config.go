@@ -3,9 +3,11 @@ package config
 import (
 	"fmt"
 	"kongtools/internal/pkg/log"
+	"kongtools/internal/pkg/xdg"
 	"kongtools/internal/view"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 
 	"github.com/spf13/cobra"
@@ -14,15 +16,22 @@ import (
 
 const (
 	// Define default configuration
-	defaultCfgStr = `# Default configuration`
-	defaultName   = ".kongtoolsrc"
+	defaultCfgStr = `# Default configuration
+# Paths may start with ~ and use environment variables, relative paths are relative to this file.`
+	defaultName = "config.yaml"
+	// legacyName is the config file in the home directory of older versions
+	legacyName = ".kongtoolsrc"
 )
 
 var (
 	// CfgFile can be set by flags to specify the config file
 	CfgFile string
 	_config config
-	once    sync.Once
+
+	readOnce sync.Once
+	readErr  error
+	loadOnce sync.Once
+	loadErr  error
 )
 
 type config struct {
@@ -30,49 +39,141 @@ type config struct {
 	App view.Config
 }
 
+// Config returns the loaded configuration, it exits when the configuration is invalid.
+// It returns the new configuration after the config file changed, see Watch.
 func Config() config {
-	once.Do(func() {
-		initConfig()
-	})
+	cobra.CheckErr(Load())
+	mutex.RLock()
+	defer mutex.RUnlock()
 	return _config
 }
 
-// initConfig reads in config file and ENV variables if set.
-func initConfig() {
-	// Find home directory.
-	home, err := os.UserHomeDir()
-	cobra.CheckErr(err)
+// Path returns the config file in use and whether it exists.
+// It is the file given by --config, or $XDG_CONFIG_HOME/kongtools/config.yaml.
+// $HOME/.kongtoolsrc is still used when it has not been migrated.
+func Path() (string, bool) {
+	if CfgFile != "" {
+		return CfgFile, exists(CfgFile)
+	}
+	path, err := defaultPath()
+	if err != nil {
+		return defaultName, false
+	}
+	if exists(path) {
+		return path, true
+	}
+	if home, err := os.UserHomeDir(); err == nil && exists(filepath.Join(home, legacyName)) {
+		return filepath.Join(home, legacyName), true
+	}
+	return path, false
+}
+
+// defaultPath returns the config file in the XDG config directory
+func defaultPath() (string, error) {
+	dir, err := xdg.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, defaultName), nil
+}
 
-	// Search config in home directory with the default name.
-	viper.AddConfigPath(home)
-	viper.SetConfigType("yaml")
-	viper.SetConfigName(defaultName)
+// Load reads and validates the config file, the default configuration is used when there is no config file.
+// It only loads once, later calls return the first result.
+func Load() error {
+	loadOnce.Do(func() {
+		loadErr = load()
+	})
+	return loadErr
+}
 
-	if CfgFile != "" {
-		// Use config file from the flag.
-		viper.SetConfigFile(CfgFile)
+func load() error {
+	path, exists := Path()
+	if exists {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if err := Validate(path, data); err != nil {
+			return err
+		}
 	}
 
-	// If a config file is found, read it in.
-	if err := viper.ReadInConfig(); err != nil {
-		switch err.(type) {
-		case viper.ConfigFileNotFoundError:
-			// Config file not found; create a new one
-			createDefaultConfig()
+	if err := read(); err != nil {
+		return err
+	}
+	if err := checkOverrides(); err != nil {
+		return err
+	}
+	var cfg config
+	if err := decode(&cfg); err != nil {
+		return fmt.Errorf("decode config file %s: %w", path, err)
+	}
+	if err := resolvePaths(&cfg, filepath.Dir(path)); err != nil {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	_config = cfg
+	return nil
+}
+
+// read reads the config file into viper without validating it
+func read() error {
+	readOnce.Do(func() {
+		viper.SetConfigType("yaml")
+		bindEnv()
+		bindChangedFlags()
+
+		path, exists := Path()
+		switch {
+		case exists:
+			viper.SetConfigFile(path)
+			readErr = viper.ReadInConfig()
+		case CfgFile != "":
+			readErr = fmt.Errorf("config file %s does not exist, create it with: kongtools config init --config %s", path, path)
 		default:
-			// Config file was found but another error was produced
-			cobra.CheckErr(err)
+			readErr = viper.ReadConfig(strings.NewReader(Default()))
 		}
-	}
+	})
+	return readErr
+}
 
-	// Print the config file used
-	fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
-	// read in environment variables that match
-	viper.AutomaticEnv()
-	// Unmarshal the config into a struct
-	cobra.CheckErr(viper.Unmarshal(&_config))
+// Get returns the value of key in use, from a flag, an environment variable or the config file.
+// key is case insensitive, e.g. log.level.
+// It returns an error when key is unknown or not set.
+func Get(key string) (any, error) {
+	canonical, t, err := lookup(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := read(); err != nil {
+		return nil, err
+	}
+	if err := checkOverrides(); err != nil {
+		return nil, err
+	}
+	if !viper.IsSet(canonical) {
+		path, _ := Path()
+		return nil, fmt.Errorf("%s is not set in %s", canonical, path)
+	}
+	if value, ok := overrideValue(canonical); ok {
+		return canonicalize(value, t), nil
+	}
+	return canonicalize(viper.Get(canonical), t), nil
+}
 
-	// fmt.Printf("Config: %+v", _config) // Debug
+// Settings returns all values in use, with keys written as in the default configuration
+func Settings() (map[string]any, error) {
+	if err := read(); err != nil {
+		return nil, err
+	}
+	if err := checkOverrides(); err != nil {
+		return nil, err
+	}
+	settings := viper.AllSettings()
+	applyOverrides(settings)
+	return canonicalize(settings, schema).(map[string]any), nil
 }
 
 // DefaultConfig returns the default configuration as a string
@@ -84,17 +185,18 @@ func DefaultConfig(configs ...string) (config string) {
 	return
 }
 
-// createDefaultConfig creates a default config file in the user's home directory
-func createDefaultConfig() {
-	home, err := os.UserHomeDir()
-	cobra.CheckErr(err)
-
-	configPath := filepath.Join(home, defaultName)
-
-	// Write the default config to the file
-	cobra.CheckErr(os.WriteFile(configPath, []byte(DefaultConfig(log.DefaultConfig, view.DefaultConfig)), 0644))
+// Default returns the default configuration of all modules
+func Default() string {
+	return DefaultConfig(log.DefaultConfig, view.DefaultConfig)
+}
 
-	fmt.Fprintf(os.Stderr, "Created default config file: %s\n", configPath)
-	viper.SetConfigFile(configPath)
-	cobra.CheckErr(viper.ReadInConfig())
+// Init writes the default configuration to path, an existing file is only overwritten when force is set
+func Init(path string, force bool) error {
+	if _, err := os.Stat(path); err == nil && !force {
+		return fmt.Errorf("config file %s already exists, use --force to overwrite it", path)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(Default()), 0o644)
 }
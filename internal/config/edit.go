@@ -0,0 +1,132 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Set sets key to value in the config file at path and writes it back, keeping comments.
+// value is parsed as YAML, e.g. true, 3, "a b" or [a, b]; an empty value clears the key.
+// A missing file is created from the default configuration. Nothing is written when the result is invalid.
+func Set(path, key, value string) error {
+	canonical, _, err := lookup(key)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		data = []byte(Default())
+	} else if err != nil {
+		return err
+	}
+
+	out, err := setValue(data, canonical, value)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if err := Validate(path, out); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, out, 0o644)
+}
+
+// setValue sets the canonical key to value in the YAML data, keeping comments. The result is not validated.
+func setValue(data []byte, canonical, value string) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+
+	valueNode, err := parseValue(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value %q: %w", value, err)
+	}
+
+	node := doc.Content[0]
+	names := strings.Split(canonical, ".")
+	for i, name := range names {
+		if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+			// "key:" without a value, turn it into a mapping
+			*node = yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", LineComment: node.LineComment}
+		}
+		if node.Kind != yaml.MappingNode {
+			return nil, fmt.Errorf("cannot set %s: %s is not a mapping", canonical, strings.Join(names[:i], "."))
+		}
+		if len(node.Content) == 0 {
+			// "key: {}", write the new entries in block style
+			node.Style &^= yaml.FlowStyle
+		}
+
+		last := i == len(names)-1
+		j := findKey(node, name)
+		if j < 0 {
+			child := valueNode
+			if !last {
+				child = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			}
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: name}, child)
+			node = child
+			continue
+		}
+		if last {
+			old := node.Content[j+1]
+			valueNode.HeadComment, valueNode.LineComment, valueNode.FootComment = old.HeadComment, old.LineComment, old.FootComment
+			node.Content[j+1] = valueNode
+			break
+		}
+		node = node.Content[j+1]
+	}
+
+	var out bytes.Buffer
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// parseValue parses a value given on the command line as a YAML node
+func parseValue(value string) (*yaml.Node, error) {
+	if strings.TrimSpace(value) == "" {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null"}, nil
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(value), &doc); err != nil {
+		return nil, err
+	}
+	node := doc.Content[0]
+	// keep values given on one line on one line, e.g. [a, b]
+	if node.Kind == yaml.SequenceNode || node.Kind == yaml.MappingNode {
+		node.Style = yaml.FlowStyle
+	}
+	return node, nil
+}
+
+// findKey returns the index of name in the mapping node, keys are case insensitive like viper
+func findKey(node *yaml.Node, name string) int {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if strings.EqualFold(node.Content[i].Value, name) {
+			return i
+		}
+	}
+	return -1
+}
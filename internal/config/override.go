@@ -0,0 +1,258 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix is the prefix of the environment variables that override config keys
+const EnvPrefix = "KONGTOOLS"
+
+// Precedence explains where the settings come from, for the help output
+const Precedence = `Every config key can be overridden by a flag or an environment variable, e.g. log.level by
+--log-level or KONGTOOLS_LOG_LEVEL. Settings are taken from, highest precedence first:
+  1. flags
+  2. KONGTOOLS_* environment variables
+  3. the config file
+  4. the default configuration
+Lists are separated by commas, e.g. --tools welcome,todo-list. Mappings are given as YAML, e.g. --board-wip-limits "{doing: 3}".
+Relative paths given by flags or environment variables are relative to the working directory.`
+
+// flagNames holds flag names that are shorter than the ones derived from the key
+var flagNames = map[string]string{
+	"app.tasksSavePath": "tasks-path",
+}
+
+// override is a config key that can be set by a flag or an environment variable
+type override struct {
+	key  string // e.g. log.maxSize
+	t    reflect.Type
+	flag string // e.g. log-max-size, without the app prefix for app keys
+	env  string // e.g. KONGTOOLS_LOG_MAX_SIZE
+}
+
+// overrides holds all keys of the schema that are not sections
+var overrides = collect(schema, "")
+
+// bound holds the flags added by BindFlags, by key
+var bound = map[string]*pflag.Flag{}
+
+func collect(t reflect.Type, prefix string) []override {
+	var result []override
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		key := joinKey(prefix, lowerFirst(field.Name))
+		if field.Type.Kind() == reflect.Struct {
+			result = append(result, collect(field.Type, key)...)
+			continue
+		}
+		words := splitWords(key)
+		flag, ok := flagNames[key]
+		if !ok {
+			flag = strings.Join(strings.Split(strings.TrimPrefix(strings.Join(words, "."), "app."), "."), "-")
+		}
+		result = append(result, override{
+			key:  key,
+			t:    field.Type,
+			flag: flag,
+			env:  EnvPrefix + "_" + strings.ToUpper(strings.Join(words, "_")),
+		})
+	}
+	return result
+}
+
+// splitWords splits a key into lower case words at dots and capitals, e.g. app.tasksSavePath to app, tasks, save, path
+func splitWords(key string) []string {
+	var words []string
+	for _, part := range strings.Split(key, ".") {
+		start := 0
+		for i, r := range part {
+			if unicode.IsUpper(r) && i > start {
+				words = append(words, strings.ToLower(part[start:i]))
+				start = i
+			}
+		}
+		words = append(words, strings.ToLower(part[start:]))
+	}
+	return words
+}
+
+// BindFlags adds a flag for every config key to flags, flags take precedence over the config file.
+// Only the flags given on the command line are bound when the config is read.
+func BindFlags(flags *pflag.FlagSet) {
+	for _, o := range overrides {
+		usage := fmt.Sprintf("%s, or $%s", o.key, o.env)
+		switch {
+		case o.t == durationType:
+			flags.Duration(o.flag, 0, usage)
+		case o.t.Kind() == reflect.Bool:
+			flags.Bool(o.flag, false, usage)
+		case o.t.Kind() == reflect.Int:
+			flags.Int(o.flag, 0, usage)
+		case o.t.Kind() == reflect.Slice:
+			flags.StringSlice(o.flag, nil, usage)
+		default:
+			// strings, and mappings as YAML
+			flags.String(o.flag, "", usage)
+		}
+		bound[o.key] = flags.Lookup(o.flag)
+	}
+}
+
+// bindChangedFlags binds the flags given on the command line,
+// the zero defaults of the other flags would hide the values of the config file
+func bindChangedFlags() {
+	for key, flag := range bound {
+		if flag.Changed {
+			must(viper.BindPFlag(key, flag))
+		}
+	}
+}
+
+// bindEnv binds the environment variable of every config key
+func bindEnv() {
+	for _, o := range overrides {
+		must(viper.BindEnv(o.key, o.env))
+	}
+}
+
+// must panics on errors that only happen with a wrong schema
+func must(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
+
+// checkOverrides checks the values of the flags and environment variables in use like the config file
+func checkOverrides() error {
+	var problems []string
+	for _, o := range overrides {
+		source, node, err := o.node()
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %s", source, err))
+			continue
+		}
+		if node == nil {
+			continue
+		}
+		var found []Problem
+		check(node, o.key, o.t, &found)
+		for _, p := range found {
+			problems = append(problems, fmt.Sprintf("%s: %s", source, p.Message))
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return errors.New("invalid flags or environment variables:\n  " + strings.Join(problems, "\n  "))
+}
+
+// node returns the value given by the flag, or else the environment variable, as a YAML node.
+// The node is nil when neither is set.
+func (o override) node() (string, *yaml.Node, error) {
+	source, value := "--"+o.flag, ""
+	if flag, ok := bound[o.key]; ok && flag.Changed {
+		value = flag.Value.String()
+		if o.t.Kind() == reflect.Slice {
+			value = strings.Trim(value, "[]")
+		}
+	} else if env := os.Getenv(o.env); env != "" {
+		source, value = "$"+o.env, env
+	} else {
+		return "", nil, nil
+	}
+
+	switch o.t.Kind() {
+	case reflect.Slice:
+		node := &yaml.Node{Kind: yaml.SequenceNode}
+		for _, item := range strings.Split(value, ",") {
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: strings.TrimSpace(item)})
+		}
+		return source, node, nil
+	case reflect.Map:
+		var doc yaml.Node
+		if err := yaml.Unmarshal([]byte(value), &doc); err != nil {
+			return source, nil, err
+		}
+		if len(doc.Content) == 0 {
+			return "", nil, nil
+		}
+		return source, doc.Content[0], nil
+	}
+	return source, &yaml.Node{Kind: yaml.ScalarNode, Value: value}, nil
+}
+
+// overridden reports whether the flag or the environment variable of key is set
+func overridden(key string) bool {
+	_, ok := overrideValue(key)
+	return ok
+}
+
+// overrideValue returns the parsed value given by the flag or the environment variable of key
+func overrideValue(key string) (any, bool) {
+	for _, o := range overrides {
+		if !strings.EqualFold(o.key, key) {
+			continue
+		}
+		_, node, err := o.node()
+		if err != nil || node == nil {
+			return nil, false
+		}
+		var value any
+		if err := node.Decode(&value); err != nil {
+			return nil, false
+		}
+		return value, true
+	}
+	return nil, false
+}
+
+// applyOverrides replaces the raw values of flags and environment variables in the settings of viper with parsed values,
+// e.g. "a,b" with [a b]
+func applyOverrides(settings map[string]any) {
+	for _, o := range overrides {
+		value, ok := overrideValue(o.key)
+		if !ok {
+			continue
+		}
+		m := settings
+		names := strings.Split(strings.ToLower(o.key), ".")
+		for _, name := range names[:len(names)-1] {
+			child, ok := m[name].(map[string]any)
+			if !ok {
+				child = map[string]any{}
+				m[name] = child
+			}
+			m = child
+		}
+		m[names[len(names)-1]] = value
+	}
+}
+
+// decode decodes the settings of viper into cfg, mappings given by flags and environment variables are parsed as YAML
+func decode(cfg *config) error {
+	return viper.Unmarshal(cfg, viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+		func(from, to reflect.Type, data any) (any, error) {
+			if from.Kind() != reflect.String || to.Kind() != reflect.Map {
+				return data, nil
+			}
+			var m map[string]any
+			err := yaml.Unmarshal([]byte(data.(string)), &m)
+			return m, err
+		},
+	)))
+}
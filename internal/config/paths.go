@@ -0,0 +1,229 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"kongtools/internal/pkg/xdg"
+	"kongtools/internal/task"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	defaultTasksName = "tasks.json"
+	defaultLogName   = "kongtools.log"
+)
+
+// resolvePaths expands ~ and environment variables in the configured paths and makes them absolute.
+// Relative paths in the config file are relative to base, the directory of the config file,
+// relative paths given by flags or environment variables are relative to the working directory.
+// Empty paths of tasks and logs default to the XDG data and state directories.
+func resolvePaths(cfg *config, base string) error {
+	if cfg.App.TasksSavePath == "" {
+		dir, err := xdg.DataDir()
+		if err != nil {
+			return err
+		}
+		cfg.App.TasksSavePath = filepath.Join(dir, defaultTasksName)
+	}
+	if cfg.Log.Filename == "" {
+		dir, err := xdg.StateDir()
+		if err != nil {
+			return err
+		}
+		cfg.Log.Filename = filepath.Join(dir, defaultLogName)
+	}
+
+	paths := []struct {
+		key  string
+		path *string
+	}{
+		{"log.filename", &cfg.Log.Filename},
+		{"app.tasksSavePath", &cfg.App.TasksSavePath},
+		{"app.theme.dir", &cfg.App.Theme.Dir},
+		{"app.session.path", &cfg.App.Session.Path},
+	}
+	for _, p := range paths {
+		dir := base
+		if overridden(p.key) {
+			wd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+			dir = wd
+		}
+		resolved, err := xdg.Resolve(*p.path, dir)
+		if err != nil {
+			return fmt.Errorf("%s: %w", p.key, err)
+		}
+		*p.path = resolved
+	}
+	return nil
+}
+
+// Move is a file moved by Migrate
+type Move struct {
+	From, To string
+}
+
+// Migrate moves the files of older versions into the XDG directories: ~/.kongtoolsrc to the config directory,
+// and the tasks and logs it put in the working or home directory to the data and state directories.
+// It does nothing when --config is given or the config file already exists, and returns the files it moved.
+func Migrate() ([]Move, error) {
+	if CfgFile != "" {
+		return nil, nil
+	}
+	path, err := defaultPath()
+	if err != nil || exists(path) {
+		return nil, err
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	legacy := filepath.Join(home, legacyName)
+	data, err := os.ReadFile(legacy)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	v := viper.New()
+	v.SetConfigType("yaml")
+	if err := v.ReadConfig(strings.NewReader(string(data))); err != nil {
+		return nil, fmt.Errorf("read %s: %w", legacy, err)
+	}
+	dataDir, err := xdg.DataDir()
+	if err != nil {
+		return nil, err
+	}
+	stateDir, err := xdg.StateDir()
+	if err != nil {
+		return nil, err
+	}
+
+	// relative paths were relative to the working directory, look for the files there and in the home directory
+	var plan []Move
+	settings := []struct {
+		key, value, dir, name string
+		related               func(path string) []string
+	}{
+		{"app.tasksSavePath", v.GetString("app.tasksSavePath"), dataDir, defaultTasksName, func(path string) []string {
+			return []string{path, task.JournalPath(path)}
+		}},
+		{"log.filename", v.GetString("log.filename"), stateDir, defaultLogName, logFiles},
+	}
+	for _, s := range settings {
+		if s.value == "" || filepath.IsAbs(s.value) || strings.HasPrefix(s.value, "~") || strings.Contains(s.value, "$") {
+			continue
+		}
+		// the old default moves to the default place, other names keep their name
+		target, value := filepath.Join(s.dir, s.name), `""`
+		if filepath.Clean(s.value) != s.name {
+			target = filepath.Join(s.dir, filepath.Base(s.value))
+			value = target
+		}
+		if src, ok := findStray(s.value, home); ok {
+			for _, from := range s.related(src) {
+				if !exists(from) {
+					continue
+				}
+				to := filepath.Join(filepath.Dir(target), stem(target)+strings.TrimPrefix(filepath.Base(from), stem(src)))
+				if exists(to) {
+					return nil, fmt.Errorf("cannot move %s, %s already exists", from, to)
+				}
+				plan = append(plan, Move{From: from, To: to})
+			}
+		} else if value != `""` {
+			// not created yet, keep the name
+			continue
+		}
+		if data, err = setValue(data, s.key, value); err != nil {
+			return nil, fmt.Errorf("update %s of %s: %w", s.key, legacy, err)
+		}
+	}
+
+	var moved []Move
+	for _, m := range plan {
+		if err := moveFile(m.From, m.To); err != nil {
+			return moved, err
+		}
+		moved = append(moved, m)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return moved, err
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return moved, err
+	}
+	if err := os.Remove(legacy); err != nil {
+		return moved, err
+	}
+	return append(moved, Move{From: legacy, To: path}), nil
+}
+
+// findStray looks for a file at the relative path in the working directory, then in the home directory
+func findStray(path, home string) (string, bool) {
+	if wd, err := os.Getwd(); err == nil {
+		if p := filepath.Join(wd, path); exists(p) {
+			return p, true
+		}
+	}
+	if p := filepath.Join(home, path); exists(p) {
+		return p, true
+	}
+	return "", false
+}
+
+// stem returns the file name without extension, related files start with it, e.g. tasks.journal.jsonl
+func stem(path string) string {
+	base := filepath.Base(path)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// logFiles returns the log file and its rotated backups, e.g. kongtools-2024-05-01T10-00-00.000.log
+func logFiles(path string) []string {
+	ext := filepath.Ext(path)
+	backups, _ := filepath.Glob(strings.TrimSuffix(path, ext) + "-*" + ext)
+	return append([]string{path}, backups...)
+}
+
+// moveFile renames src to dst, copying when they are on different file systems
+func moveFile(src, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Remove(src)
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
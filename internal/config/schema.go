@@ -0,0 +1,390 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"kongtools/internal/i18n"
+	"kongtools/internal/ui"
+	"kongtools/internal/view"
+	"log/slog"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// schema is the structure of the config file, keys match field names case insensitively like viper
+var schema = reflect.TypeOf(config{})
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Problem is a problem found in a config file
+type Problem struct {
+	Line    int    // 0 when unknown
+	Key     string // e.g. log.level
+	Message string
+}
+
+func (p Problem) String() string {
+	s := p.Message
+	if p.Key != "" {
+		s = p.Key + ": " + s
+	}
+	if p.Line > 0 {
+		s = fmt.Sprintf("line %d: %s", p.Line, s)
+	}
+	return s
+}
+
+// ValidationError lists the problems of an invalid config file
+type ValidationError struct {
+	Path     string
+	Problems []Problem
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "invalid config file %s:", e.Path)
+	for _, p := range e.Problems {
+		b.WriteString("\n  " + p.String())
+	}
+	return b.String()
+}
+
+// rule checks the decoded value of a key
+type rule func(value any) error
+
+// rules holds the value checks by lower case key
+var rules = map[string]rule{
+	"log.level": func(value any) error {
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(value.(string))); err != nil {
+			return fmt.Errorf("unknown level %q, use debug, info, warn or error", value)
+		}
+		return nil
+	},
+	"log.maxsize":    nonNegative,
+	"log.maxbackups": nonNegative,
+	"log.maxage":     nonNegative,
+
+	"app.tools": func(value any) error {
+		return oneOfEach(value.([]string), view.ToolNames())
+	},
+	"app.language": func(value any) error {
+		lang := strings.ToLower(value.(string))
+		if lang == "" || lang == i18n.Auto || slices.Contains(i18n.Languages(), lang) {
+			return nil
+		}
+		return fmt.Errorf("unsupported language %q, use %s or %s", value, i18n.Auto, strings.Join(i18n.Languages(), ", "))
+	},
+	"app.welcome.sections": func(value any) error {
+		return oneOfEach(value.([]string), view.WelcomeSections)
+	},
+	"app.welcome.refreshinterval": func(value any) error {
+		if value.(time.Duration) < 0 {
+			return errors.New("must not be negative")
+		}
+		return nil
+	},
+	"app.todo.titlelimit": nonNegative,
+	"app.board.statuses": func(value any) error {
+		if statuses := value.([]string); len(statuses) == 1 {
+			return errors.New("needs at least two statuses")
+		}
+		return nil
+	},
+	"app.board.wiplimits": func(value any) error {
+		for status, limit := range value.(map[string]int) {
+			if limit < 0 {
+				return fmt.Errorf("limit of %q must not be negative", status)
+			}
+		}
+		return nil
+	},
+	"app.keys.preset": func(value any) error {
+		if preset := value.(string); preset != "" && !slices.Contains(ui.KeyPresets(), preset) {
+			return fmt.Errorf("unknown preset %q, use %s", preset, strings.Join(ui.KeyPresets(), " or "))
+		}
+		return nil
+	},
+	"app.keys.bindings": func(value any) error {
+		known, err := keyActions()
+		if err != nil {
+			return err
+		}
+		for scope, actions := range value.(map[string]map[string][]string) {
+			names, ok := known[scope]
+			if !ok {
+				return fmt.Errorf("unknown scope %q, use %s", scope, strings.Join(sortedKeys(known), ", "))
+			}
+			for action, keys := range actions {
+				if !slices.Contains(names, action) {
+					return fmt.Errorf("unknown action %s.%s, use %s", scope, action, strings.Join(names, ", "))
+				}
+				for _, seq := range keys {
+					for _, key := range strings.Fields(seq) {
+						if _, err := ui.ParseKeyEvent(key); err != nil {
+							return fmt.Errorf("%s.%s: %w", scope, action, err)
+						}
+					}
+				}
+			}
+		}
+		return nil
+	},
+}
+
+// keyActions holds the actions that can be bound, by scope, of an app with all tools enabled
+var keyActions = sync.OnceValues(view.KeyActions)
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func nonNegative(value any) error {
+	if value.(int) < 0 {
+		return errors.New("must not be negative")
+	}
+	return nil
+}
+
+// oneOfEach checks that every value is one of valid
+func oneOfEach(values, valid []string) error {
+	for _, v := range values {
+		if !slices.Contains(valid, v) {
+			return fmt.Errorf("unknown value %q, use %s", v, strings.Join(valid, ", "))
+		}
+	}
+	return nil
+}
+
+// Validate checks YAML config data against the schema and the value rules.
+// It returns a *ValidationError listing all problems, path is only used in the message.
+func Validate(path string, data []byte) error {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return &ValidationError{Path: path, Problems: []Problem{{Message: err.Error()}}}
+	}
+	if len(doc.Content) == 0 {
+		return nil
+	}
+
+	var problems []Problem
+	check(doc.Content[0], "", schema, &problems)
+	if len(problems) == 0 {
+		return nil
+	}
+	return &ValidationError{Path: path, Problems: problems}
+}
+
+// check checks that node fits type t, key is the full key of node
+func check(node *yaml.Node, key string, t reflect.Type, problems *[]Problem) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		return
+	}
+	add := func(line int, key, format string, args ...any) {
+		*problems = append(*problems, Problem{Line: line, Key: key, Message: fmt.Sprintf(format, args...)})
+	}
+
+	switch {
+	case t.Kind() == reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			add(node.Line, key, "expected a mapping")
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			name, value := node.Content[i], node.Content[i+1]
+			field, ok := fieldByName(t, name.Value)
+			if !ok {
+				add(name.Line, joinKey(key, name.Value), "unknown key, %s", suggest(t, name.Value))
+				continue
+			}
+			check(value, joinKey(key, lowerFirst(field.Name)), field.Type, problems)
+		}
+	case t.Kind() == reflect.Map:
+		if node.Kind != yaml.MappingNode {
+			add(node.Line, key, "expected a mapping")
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			check(node.Content[i+1], joinKey(key, node.Content[i].Value), t.Elem(), problems)
+		}
+	case t.Kind() == reflect.Slice:
+		if node.Kind != yaml.SequenceNode {
+			add(node.Line, key, "expected a list, such as [a, b]")
+			return
+		}
+		for i, item := range node.Content {
+			check(item, fmt.Sprintf("%s[%d]", key, i), t.Elem(), problems)
+		}
+	default:
+		if node.Kind != yaml.ScalarNode || node.Decode(reflect.New(t).Interface()) != nil {
+			add(node.Line, key, "expected %s, got %s", typeName(t), describe(node))
+			return
+		}
+	}
+
+	// check the value once the structure is right
+	r, ok := rules[strings.ToLower(key)]
+	if !ok {
+		return
+	}
+	value := reflect.New(t)
+	if err := node.Decode(value.Interface()); err != nil {
+		// type errors of the items are already reported
+		return
+	}
+	if err := r(value.Elem().Interface()); err != nil {
+		add(node.Line, key, "%s", err)
+	}
+}
+
+// lookup checks that key is in the schema and returns it written as in the schema, with its type.
+// Any key is accepted inside a map.
+func lookup(key string) (string, reflect.Type, error) {
+	t := schema
+	var keys []string
+	for _, name := range strings.Split(key, ".") {
+		switch t.Kind() {
+		case reflect.Struct:
+			field, ok := fieldByName(t, name)
+			if !ok {
+				return "", nil, fmt.Errorf("unknown key %q, %s", joinKey(strings.Join(keys, "."), name), suggest(t, name))
+			}
+			keys = append(keys, lowerFirst(field.Name))
+			t = field.Type
+		case reflect.Map:
+			keys = append(keys, name)
+			t = t.Elem()
+		default:
+			return "", nil, fmt.Errorf("unknown key %q, %s has no sub keys", key, strings.Join(keys, "."))
+		}
+	}
+	return strings.Join(keys, "."), t, nil
+}
+
+// canonicalize rewrites the lower case keys from viper as in the schema, e.g. titlelimit to titleLimit
+func canonicalize(value any, t reflect.Type) any {
+	m, ok := value.(map[string]any)
+	if !ok {
+		return value
+	}
+	result := make(map[string]any, len(m))
+	for k, v := range m {
+		switch t.Kind() {
+		case reflect.Struct:
+			if field, ok := fieldByName(t, k); ok {
+				result[lowerFirst(field.Name)] = canonicalize(v, field.Type)
+				continue
+			}
+			result[k] = v
+		case reflect.Map:
+			result[k] = canonicalize(v, t.Elem())
+		default:
+			result[k] = v
+		}
+	}
+	return result
+}
+
+func fieldByName(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		if field := t.Field(i); field.IsExported() && strings.EqualFold(field.Name, name) {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// suggest returns a hint for an unknown key: a similar key, or all valid keys
+func suggest(t reflect.Type, name string) string {
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		if field := t.Field(i); field.IsExported() {
+			names = append(names, lowerFirst(field.Name))
+		}
+	}
+	best, distance := "", 3
+	for _, n := range names {
+		if d := levenshtein(strings.ToLower(n), strings.ToLower(name)); d < distance {
+			best, distance = n, d
+		}
+	}
+	if best != "" {
+		return fmt.Sprintf("did you mean %q?", best)
+	}
+	sort.Strings(names)
+	return "valid keys: " + strings.Join(names, ", ")
+}
+
+// levenshtein returns the edit distance of a and b
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur := make([]int, len(rb)+1)
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(rb)]
+}
+
+func typeName(t reflect.Type) string {
+	if t == durationType {
+		return "a duration, such as 500ms, 1s or 5m"
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "true or false"
+	case reflect.Int, reflect.Int64:
+		return "an integer"
+	}
+	return "a string"
+}
+
+func describe(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "a mapping"
+	case yaml.SequenceNode:
+		return "a list"
+	}
+	return fmt.Sprintf("%q", node.Value)
+}
+
+func joinKey(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
+
+// lowerFirst returns a field name as written in the config, e.g. TitleLimit to titleLimit
+func lowerFirst(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToLower(r)) + s[size:]
+}
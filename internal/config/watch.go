@@ -0,0 +1,129 @@
+package config
+
+import (
+	"kongtools/internal/pkg/log"
+	"kongtools/internal/view"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+)
+
+// reloadDelay waits for the editor to finish writing, the events in between cause one reload
+const reloadDelay = 200 * time.Millisecond
+
+var (
+	mutex       sync.RWMutex
+	onChange    []func(log log.Config, app view.Config) error
+	onError     []func(error)
+	reloadTimer *time.Timer
+	watchOnce   sync.Once
+	// reloading serializes reloads, a reload may still run when the next timer fires
+	reloading sync.Mutex
+)
+
+// OnChange adds a function that applies the new configuration after the config file changed.
+// It is called in the watching goroutine before Config returns the new configuration.
+// When a function returns an error the change is rejected: the functions called before it apply the previous
+// configuration again, Config keeps returning the previous configuration and the OnError functions are called.
+func OnChange(fn func(log log.Config, app view.Config) error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	onChange = append(onChange, fn)
+}
+
+// OnError adds a function called when the changed config file is invalid or cannot be applied,
+// the previous configuration is kept. It is called in the watching goroutine.
+func OnError(fn func(error)) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	onError = append(onError, fn)
+}
+
+// Watch watches the config file and reloads it after it changed.
+// Nothing is watched when the default configuration is used without a file.
+func Watch() {
+	watchOnce.Do(func() {
+		if _, exists := Path(); !exists {
+			return
+		}
+		viper.OnConfigChange(func(fsnotify.Event) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			if reloadTimer != nil {
+				reloadTimer.Stop()
+			}
+			reloadTimer = time.AfterFunc(reloadDelay, reload)
+		})
+		viper.WatchConfig()
+	})
+}
+
+// reload validates and loads the changed config file, and lets the subscribers apply it.
+// The new configuration is only used when all subscribers applied it.
+func reload() {
+	reloading.Lock()
+	defer reloading.Unlock()
+
+	path, _ := Path()
+	next, err := parse(path)
+
+	mutex.RLock()
+	prev := _config
+	changeHandlers, errorHandlers := onChange, onError
+	mutex.RUnlock()
+
+	if err == nil && !reflect.DeepEqual(prev, next) {
+		err = apply(changeHandlers, prev, next)
+	}
+	if err != nil {
+		for _, fn := range errorHandlers {
+			fn(err)
+		}
+		return
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	_config = next
+}
+
+// apply calls the handlers with next, after an error the handlers already called get prev again
+func apply(handlers []func(log.Config, view.Config) error, prev, next config) error {
+	for i, fn := range handlers {
+		if err := fn(next.Log, next.App); err != nil {
+			for _, undo := range handlers[:i] {
+				// best effort, the error to report is the first one
+				_ = undo(prev.Log, prev.App)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// parse reads the config file at path into a new configuration
+func parse(path string) (config, error) {
+	var next config
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return next, err
+	}
+	if err := Validate(path, data); err != nil {
+		return next, err
+	}
+	if err := viper.ReadInConfig(); err != nil {
+		return next, err
+	}
+	if err := decode(&next); err != nil {
+		return next, err
+	}
+	if err := resolvePaths(&next, filepath.Dir(path)); err != nil {
+		return next, err
+	}
+	return next, nil
+}
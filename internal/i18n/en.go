@@ -0,0 +1,228 @@
+package i18n
+
+// en 英文, 所有的键以它为准
+var en = map[string]string{
+	"list.separator": ", ",
+
+	"weekday.0":       "Sunday",
+	"weekday.1":       "Monday",
+	"weekday.2":       "Tuesday",
+	"weekday.3":       "Wednesday",
+	"weekday.4":       "Thursday",
+	"weekday.5":       "Friday",
+	"weekday.6":       "Saturday",
+	"weekday.short.0": "Sun",
+	"weekday.short.1": "Mon",
+	"weekday.short.2": "Tue",
+	"weekday.short.3": "Wed",
+	"weekday.short.4": "Thu",
+	"weekday.short.5": "Fri",
+	"weekday.short.6": "Sat",
+
+	"menu.title":         "Menu",
+	"menu.switch_to":     "Switch to %s",
+	"menu.quit":          "Quit",
+	"menu.quit_hint":     "Press to exit",
+	"split.open":         "Open %s in the split pane",
+	"split.no_page":      "No other page to open in the split pane.",
+	"split.already_open": "%s is already open.",
+
+	"app.config_reloaded": "Config reloaded.",
+	"app.config_restart":  "Config reloaded, some changes apply after restart.",
+	"app.config_invalid":  "Config not reloaded, keeping the previous config: %s",
+	"app.theme_skipped":   "Theme file skipped: %s",
+
+	"action.global.quit":           "Quit",
+	"action.global.focus-menu":     "Focus the menu",
+	"action.global.focus-content":  "Focus the current page",
+	"action.global.theme":          "Switch to the next theme",
+	"action.global.palette":        "Open the command palette",
+	"action.global.messages":       "Show the message history",
+	"action.global.back":           "Go back to the previous page",
+	"action.global.forward":        "Go forward to the next page",
+	"action.global.sidebar":        "Collapse or expand the sidebar",
+	"action.global.sidebar-shrink": "Make the sidebar narrower",
+	"action.global.sidebar-grow":   "Make the sidebar wider",
+	"action.global.split":          "Split side by side, stacked, or close the split",
+	"action.global.focus-pane":     "Focus the other pane of the split",
+	"action.global.help":           "Show key bindings",
+
+	"dialog.ok":     "OK",
+	"dialog.cancel": "Cancel",
+	"dialog.close":  "Close",
+
+	"palette.title":       "Commands",
+	"palette.placeholder": "Type to search commands",
+	"palette.recent":      "recent",
+
+	"status.commands":  "commands",
+	"status.messages":  "messages",
+	"messages.title":   "Messages",
+	"messages.empty":   "No messages yet.",
+	"severity.info":    "info",
+	"severity.success": "success",
+	"severity.warning": "warning",
+	"severity.error":   "error",
+
+	"keys.title":        "Keys",
+	"keys.description":  "Key bindings",
+	"keys.unbound":      "(unbound)",
+	"keys.scope.global": "Global",
+	"keys.scope.menu":   "Menu",
+	"keys.scope.split":  "Split pane",
+	"keys.scope.note":   "Note editor",
+
+	"welcome.name":            "Welcome",
+	"welcome.description":     "Welcome page",
+	"welcome.title":           "Welcome to KongTools",
+	"welcome.clock":           "%s, %s",
+	"welcome.unknown_section": "Unknown welcome section: %s",
+	"welcome.due_today":       "Due today (%d)",
+	"welcome.overdue":         "Overdue (%d)",
+	"welcome.more":            "... and %d more",
+	"welcome.tip":             "Tip: %s",
+	"welcome.recent":          "Recently used: %s",
+
+	"welcome.tip.palette":       "Press Ctrl+P to search and run any command.",
+	"welcome.tip.todo":          "Press t in the menu to open the To-Do list.",
+	"welcome.tip.complete":      "Press Space on a task to mark it as completed.",
+	"welcome.tip.timer":         "Press s on a task to start or stop its timer.",
+	"welcome.tip.due":           "Press d on a task to set its due date.",
+	"welcome.tip.entries":       "Press T on a task to edit its time entries.",
+	"welcome.tip.board":         "On the board, Shift+←/→ or H/L moves a card to another column.",
+	"welcome.tip.calendar_week": "In the calendar, press w for the weekly agenda and m for the month.",
+	"welcome.tip.calendar_open": "In the calendar, press Enter on a day to list the tasks due that day.",
+	"welcome.tip.report":        "Run `kongtools report --group-by tag` to see where the time went.",
+
+	"todo.name":                "Todo List",
+	"todo.description":         "Todo list page",
+	"todo.title":               "To-Do List",
+	"todo.title_filtered":      "To-Do List (%s, Esc to clear)",
+	"todo.filter_due":          "due %s",
+	"todo.label_new":           "New To-Do: ",
+	"todo.label_edit":          "Edit To-Do: ",
+	"todo.label_due":           "Due (%s): ",
+	"todo.invalid_filter":      "Invalid due date filter: %s",
+	"todo.invalid_due":         "Invalid due date, use %s or %s.",
+	"todo.delete_editing":      "Cannot delete while editing a task.",
+	"todo.entries_editing":     "Cannot edit time entries while editing a task.",
+	"todo.empty":               "Task list is empty. Add a task first.",
+	"todo.delete_title":        "Delete task",
+	"todo.delete_message":      "Delete \"%s\"?",
+	"todo.delete_ok":           "Delete",
+	"todo.changed":             "The task has changed, please try again.",
+	"todo.too_long":            "Task title should not exceed %d columns, CJK characters and emoji count 2.",
+	"todo.empty_title":         "Task title is empty, only tags, priority, due date or recurrence were given.",
+	"todo.save_failed":         "Failed to save tasks",
+	"todo.saved":               "Tasks saved to file: %s",
+	"todo.title_selected":      "· %d selected",
+	"todo.bulk_done":           "%s: %d task(s). Press %s to undo.",
+	"todo.bulk_delete_message": "Delete %d selected tasks?",
+	"todo.tag_title":           "Tag tasks",
+	"todo.tag_label":           "Tags (-tag removes): ",
+	"todo.tag_empty":           "Enter at least one tag.",
+	"todo.move_title":          "Move tasks",
+	"todo.move_label":          "Status (%s): ",
+	"todo.invalid_status":      "Unknown status, use one of: %s",
+	"todo.nothing_to_undo":     "Nothing to undo.",
+	"todo.undone":              "Undone: %s",
+	"todo.bulk.add":            "Add task",
+	"todo.bulk.edit":           "Edit task",
+	"todo.bulk.due":            "Set due date",
+	"todo.bulk.complete":       "Complete",
+	"todo.bulk.reopen":         "Reopen",
+	"todo.bulk.delete":         "Delete",
+	"todo.bulk.tag":            "Tag",
+	"todo.bulk.move":           "Move to %s",
+	"todo.bulk.timer_start":    "Start timer",
+	"todo.bulk.timer_stop":     "Stop timer",
+	"todo.bulk.entries":        "Edit time entries",
+	"todo.bulk.note":           "Edit note",
+
+	"todo.help.write":    "💡Write your first to-do task in the input field above.",
+	"todo.help.add":      "👏Press Enter to add the task to the list.",
+	"todo.help.edit":     "📝Select a task and press Enter to edit it.",
+	"todo.help.cancel":   "🤷Press Esc to cancel editing a task.",
+	"todo.help.delete":   "🥷Press Delete to remove a selected task.",
+	"todo.help.complete": "✅Press Space to mark a task as completed.",
+	"todo.help.timer":    "⏱ Press s to start or stop the timer of a task.",
+	"todo.help.entries":  "🕒Press T to edit the time entries of a task.",
+	"todo.help.due":      "📅Press d to set the due date of a task.",
+	"todo.help.quickadd": "🏷 Type #tag !high @tomorrow 18:00 every week to set tags, priority, due date and recurrence.",
+	"todo.help.select":   "☑ Press m to mark tasks, v to select a range or Ctrl+A to select all, then act on them at once. Press u to undo.",
+
+	"action.todo-list.complete":   "Mark the task as completed or not",
+	"action.todo-list.timer":      "Start or stop the timer of the task",
+	"action.todo-list.entries":    "Edit the time entries of the task",
+	"action.todo-list.due":        "Set the due date of the task",
+	"action.todo-list.delete":     "Delete the task",
+	"action.todo-list.edit":       "Edit the task, or save the edit",
+	"action.todo-list.cancel":     "Cancel editing, or clear the filter",
+	"action.todo-list.input":      "Focus the input field to add a task",
+	"action.todo-list.note":       "Edit the note of the task",
+	"action.todo-list.mark":       "Mark or unmark the task",
+	"action.todo-list.range":      "Start or finish selecting a range",
+	"action.todo-list.select-all": "Select all tasks in the list",
+	"action.todo-list.tag":        "Add or remove tags of the selected tasks",
+	"action.todo-list.move":       "Move the selected tasks to another status",
+	"action.todo-list.undo":       "Undo the last change",
+
+	"action.note.save":    "Save the note",
+	"action.note.preview": "Switch between editing and Markdown preview",
+	"action.note.editor":  "Edit the note in $EDITOR",
+	"action.note.cancel":  "Close the note editor",
+
+	"note.title":           "Note: %s",
+	"note.placeholder":     "Write a note in Markdown: # heading, - list, `code`, **bold**",
+	"note.mode_edit":       "edit",
+	"note.mode_preview":    "preview",
+	"note.hint.save":       "save",
+	"note.hint.preview":    "preview",
+	"note.hint.editor":     "$EDITOR",
+	"note.hint.cancel":     "close",
+	"note.editing":         "Cannot edit the note while editing a task.",
+	"note.editor_failed":   "Failed to edit the note",
+	"note.discard_title":   "Discard changes",
+	"note.discard_message": "The note has unsaved changes. Discard them?",
+	"note.discard_ok":      "Discard",
+
+	"entries.title":            "Time entries: %s",
+	"entries.start":            "#%d start",
+	"entries.end":              "#%d end",
+	"entries.add":              "Add",
+	"entries.save":             "Save",
+	"entries.invalid":          "Invalid time entries",
+	"entries.invalid_start":    "entry #%d: invalid start time, use %s",
+	"entries.invalid_end":      "entry #%d: invalid end time, use %s",
+	"entries.end_before_start": "entry #%d: end time is before start time",
+	"entries.one_running":      "only one entry can be running",
+	"entries.remove_title":     "Remove time entries",
+	"entries.remove_message":   "Remove %d time entries of \"%s\"?",
+	"entries.remove_ok":        "Remove",
+
+	"board.title":       "Board",
+	"board.description": "Kanban board page",
+	"board.wip_reached": "WIP limit of %q reached (%d).",
+
+	"action.board.left":       "Focus the column on the left",
+	"action.board.right":      "Focus the column on the right",
+	"action.board.move-left":  "Move the card to the column on the left",
+	"action.board.move-right": "Move the card to the column on the right",
+
+	"calendar.title":        "Calendar",
+	"calendar.description":  "Tasks by due date",
+	"calendar.month_layout": "January 2006",
+	"calendar.week_title":   "Week %d, %d",
+	"calendar.today":        "today",
+	"calendar.month_help":   "←→↑↓ day  </> month  w week  t today  Enter open",
+	"calendar.week_help":    "←→↑↓ day  </> week  m month  t today  Enter open",
+
+	"stats.name":        "Stats",
+	"stats.description": "Productivity statistics",
+	"stats.title":       "Statistics",
+	"stats.created":     "Created per day (%dd)",
+	"stats.completed":   "Completed per day (%dd)",
+	"stats.tag_time":    "Time per tag (%dd)",
+	"stats.summary": " Open %s%d[-]   Overdue %s%d[-]   Created %s%d[-]   Completed %s%d[-]\n" +
+		" Streak %s%d[-] days   Longest %s%d[-] days",
+}
@@ -0,0 +1,148 @@
+// Package i18n 界面文本的多语言目录
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 支持的语言
+const (
+	English = "en"
+	Chinese = "zh"
+)
+
+// Auto 按环境变量选择语言
+const Auto = "auto"
+
+// catalogs 语言 -> 键 -> 文本, 文本可以包含fmt的格式
+var catalogs = map[string]map[string]string{
+	English: en,
+	Chinese: zh,
+}
+
+var (
+	mutex    sync.RWMutex
+	language = English
+)
+
+// Languages 支持的语言, 按名称排序
+func Languages() []string {
+	languages := make([]string, 0, len(catalogs))
+	for lang := range catalogs {
+		languages = append(languages, lang)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// Detect 按LC_ALL, LC_MESSAGES, LANG选择语言, 都不支持时为英文
+func Detect() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+		// 如 zh_CN.UTF-8, en_US, C
+		lang, _, _ := strings.Cut(value, ".")
+		lang, _, _ = strings.Cut(lang, "_")
+		lang = strings.ToLower(lang)
+		if _, ok := catalogs[lang]; ok {
+			return lang
+		}
+		return English
+	}
+	return English
+}
+
+// SetLanguage 设置界面语言, 为空或auto时按环境变量选择
+func SetLanguage(lang string) error {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if lang == "" || lang == Auto {
+		lang = Detect()
+	}
+	if _, ok := catalogs[lang]; !ok {
+		return fmt.Errorf("unsupported language %q, supported: %s, %s", lang, Auto, strings.Join(Languages(), ", "))
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	language = lang
+	return nil
+}
+
+// Language 当前的界面语言
+func Language() string {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return language
+}
+
+// T 当前语言中key的文本, 有args时按fmt格式化.
+// 当前语言缺少时用英文, 都缺少时返回key.
+func T(key string, args ...any) string {
+	text, ok := catalogs[Language()][key]
+	if !ok {
+		if text, ok = en[key]; !ok {
+			text = key
+		}
+	}
+	if len(args) == 0 {
+		return text
+	}
+	return fmt.Sprintf(text, args...)
+}
+
+// Weekday 星期的全称
+func Weekday(day time.Weekday) string {
+	return T(fmt.Sprintf("weekday.%d", day))
+}
+
+// ShortWeekday 星期的简称
+func ShortWeekday(day time.Weekday) string {
+	return T(fmt.Sprintf("weekday.short.%d", day))
+}
+
+// verbPattern fmt的格式
+var verbPattern = regexp.MustCompile(`%[-+# 0]*(\*|\d+)?(\.\d+)?[a-zA-Z%]`)
+
+// Check 检查各语言的键是否与英文一致, 格式是否相同
+func Check() error {
+	var problems []string
+	for _, lang := range Languages() {
+		catalog := catalogs[lang]
+		for key, text := range en {
+			translated, ok := catalog[key]
+			if !ok {
+				problems = append(problems, fmt.Sprintf("%s: missing key %q", lang, key))
+				continue
+			}
+			if !sameVerbs(text, translated) {
+				problems = append(problems, fmt.Sprintf("%s: key %q has different format verbs", lang, key))
+			}
+		}
+		for key := range catalog {
+			if _, ok := en[key]; !ok {
+				problems = append(problems, fmt.Sprintf("%s: unknown key %q", lang, key))
+			}
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	sort.Strings(problems)
+	return fmt.Errorf("invalid message catalog:\n%s", strings.Join(problems, "\n"))
+}
+
+// sameVerbs 两段文本的格式是否相同
+func sameVerbs(a, b string) bool {
+	va, vb := verbPattern.FindAllString(a, -1), verbPattern.FindAllString(b, -1)
+	sort.Strings(va)
+	sort.Strings(vb)
+	return strings.Join(va, " ") == strings.Join(vb, " ")
+}
@@ -0,0 +1,140 @@
+package i18n
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestCatalogs(t *testing.T) {
+	if err := Check(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheckFindsProblems(t *testing.T) {
+	tests := []struct {
+		name string
+		zh   map[string]string
+		want string
+	}{
+		{"missing key", map[string]string{}, `zh: missing key "greeting"`},
+		{"unknown key", map[string]string{"greeting": "你好 %s", "farewell": "再见"}, `zh: unknown key "farewell"`},
+		{"missing verb", map[string]string{"greeting": "你好"}, `zh: key "greeting" has different format verbs`},
+		{"different verb", map[string]string{"greeting": "你好 %d"}, `zh: key "greeting" has different format verbs`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withCatalogs(t, map[string]string{"greeting": "Hello %s"}, tt.zh)
+			err := Check()
+			if err == nil {
+				t.Fatal("Check() = nil, want an error")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Check() = %q, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// withCatalogs 在测试期间替换英文和中文目录
+func withCatalogs(t *testing.T, enCatalog, zhCatalog map[string]string) {
+	t.Helper()
+	saved, savedEn := catalogs, en
+	catalogs, en = map[string]map[string]string{English: enCatalog, Chinese: zhCatalog}, enCatalog
+	t.Cleanup(func() { catalogs, en = saved, savedEn })
+}
+
+func TestSameVerbs(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"plain", "纯文本", true},
+		{"%s of %d", "%d 个 %s", true},
+		{"100%%: %v", "%v：100%%", true},
+		{"%-10s", "%-10s", true},
+		{"%s", "%v", false},
+		{"%s and %s", "%s", false},
+		{"%.1f%%", "%.2f%%", false},
+	}
+	for _, tt := range tests {
+		if got := sameVerbs(tt.a, tt.b); got != tt.want {
+			t.Errorf("sameVerbs(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+// TestKeysInSource 检查internal下所有 i18n.T("...") 使用的键在每种语言中都有文本
+func TestKeysInSource(t *testing.T) {
+	keys := map[string][]string{} // 键 -> 使用的位置
+	fset := token.NewFileSet()
+	err := filepath.WalkDir("..", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".go") {
+			return err
+		}
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return err
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			if key, ok := literalKey(n, file.Name.Name); ok {
+				keys[key] = append(keys[key], fset.Position(n.Pos()).String())
+			}
+			return true
+		})
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) == 0 {
+		t.Fatal("no i18n.T calls found")
+	}
+
+	var problems []string
+	for key, positions := range keys {
+		for _, lang := range Languages() {
+			if _, ok := catalogs[lang][key]; !ok {
+				problems = append(problems, fmt.Sprintf("%s: key %q missing in %s", positions[0], key, lang))
+			}
+		}
+	}
+	sort.Strings(problems)
+	for _, p := range problems {
+		t.Error(p)
+	}
+}
+
+// literalKey 调用T时第一个参数为字符串字面量则返回它, 在i18n包中调用时不带包名
+func literalKey(n ast.Node, pkg string) (string, bool) {
+	call, ok := n.(*ast.CallExpr)
+	if !ok || len(call.Args) == 0 {
+		return "", false
+	}
+	switch fun := call.Fun.(type) {
+	case *ast.SelectorExpr:
+		if x, ok := fun.X.(*ast.Ident); !ok || x.Name != "i18n" || fun.Sel.Name != "T" {
+			return "", false
+		}
+	case *ast.Ident:
+		if pkg != "i18n" || fun.Name != "T" {
+			return "", false
+		}
+	default:
+		return "", false
+	}
+	lit, ok := call.Args[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	key, err := strconv.Unquote(lit.Value)
+	return key, err == nil
+}
@@ -0,0 +1,228 @@
+package i18n
+
+// zh 中文
+var zh = map[string]string{
+	"list.separator": "、",
+
+	"weekday.0":       "星期日",
+	"weekday.1":       "星期一",
+	"weekday.2":       "星期二",
+	"weekday.3":       "星期三",
+	"weekday.4":       "星期四",
+	"weekday.5":       "星期五",
+	"weekday.6":       "星期六",
+	"weekday.short.0": "日",
+	"weekday.short.1": "一",
+	"weekday.short.2": "二",
+	"weekday.short.3": "三",
+	"weekday.short.4": "四",
+	"weekday.short.5": "五",
+	"weekday.short.6": "六",
+
+	"menu.title":         "菜单",
+	"menu.switch_to":     "切换到%s",
+	"menu.quit":          "退出",
+	"menu.quit_hint":     "按下退出程序",
+	"split.open":         "在分屏中打开%s",
+	"split.no_page":      "没有其他页面可以在分屏中打开。",
+	"split.already_open": "%s已经打开。",
+
+	"app.config_reloaded": "配置已重新加载。",
+	"app.config_restart":  "配置已重新加载，部分修改在重启后生效。",
+	"app.config_invalid":  "配置无效，继续使用原来的配置：%s",
+	"app.theme_skipped":   "已跳过主题文件：%s",
+
+	"action.global.quit":           "退出",
+	"action.global.focus-menu":     "聚焦菜单",
+	"action.global.focus-content":  "聚焦当前页面",
+	"action.global.theme":          "切换到下一个主题",
+	"action.global.palette":        "打开命令面板",
+	"action.global.messages":       "查看历史消息",
+	"action.global.back":           "后退到上一个页面",
+	"action.global.forward":        "前进到下一个页面",
+	"action.global.sidebar":        "折叠或展开侧边栏",
+	"action.global.sidebar-shrink": "缩窄侧边栏",
+	"action.global.sidebar-grow":   "加宽侧边栏",
+	"action.global.split":          "左右分屏，上下分屏或关闭分屏",
+	"action.global.focus-pane":     "聚焦分屏的另一侧",
+	"action.global.help":           "查看按键",
+
+	"dialog.ok":     "确定",
+	"dialog.cancel": "取消",
+	"dialog.close":  "关闭",
+
+	"palette.title":       "命令",
+	"palette.placeholder": "输入以搜索命令",
+	"palette.recent":      "最近",
+
+	"status.commands":  "命令",
+	"status.messages":  "消息",
+	"messages.title":   "消息",
+	"messages.empty":   "还没有消息。",
+	"severity.info":    "信息",
+	"severity.success": "成功",
+	"severity.warning": "警告",
+	"severity.error":   "错误",
+
+	"keys.title":        "按键",
+	"keys.description":  "按键列表",
+	"keys.unbound":      "(未绑定)",
+	"keys.scope.global": "全局",
+	"keys.scope.menu":   "菜单",
+	"keys.scope.split":  "分屏",
+	"keys.scope.note":   "备注编辑器",
+
+	"welcome.name":            "欢迎",
+	"welcome.description":     "欢迎页",
+	"welcome.title":           "欢迎使用 KongTools",
+	"welcome.clock":           "%s %s",
+	"welcome.unknown_section": "未知的欢迎页区块: %s",
+	"welcome.due_today":       "今天到期 (%d)",
+	"welcome.overdue":         "已过期 (%d)",
+	"welcome.more":            "... 还有 %d 项",
+	"welcome.tip":             "提示: %s",
+	"welcome.recent":          "最近使用: %s",
+
+	"welcome.tip.palette":       "按 Ctrl+P 搜索并执行任意命令。",
+	"welcome.tip.todo":          "在菜单中按 t 打开待办列表。",
+	"welcome.tip.complete":      "在任务上按空格标记为已完成。",
+	"welcome.tip.timer":         "在任务上按 s 开始或停止计时。",
+	"welcome.tip.due":           "在任务上按 d 设置截止日期。",
+	"welcome.tip.entries":       "在任务上按 T 编辑计时记录。",
+	"welcome.tip.board":         "在看板中，Shift+←/→ 或 H/L 把卡片移到其他列。",
+	"welcome.tip.calendar_week": "在日历中，按 w 查看周日程，按 m 查看月视图。",
+	"welcome.tip.calendar_open": "在日历中，在某天上按回车列出当天到期的任务。",
+	"welcome.tip.report":        "运行 `kongtools report --group-by tag` 查看时间花在哪里。",
+
+	"todo.name":                "待办列表",
+	"todo.description":         "待办列表页",
+	"todo.title":               "待办列表",
+	"todo.title_filtered":      "待办列表 (%s，Esc 清除)",
+	"todo.filter_due":          "%s 到期",
+	"todo.label_new":           "新待办: ",
+	"todo.label_edit":          "编辑待办: ",
+	"todo.label_due":           "截止 (%s): ",
+	"todo.invalid_filter":      "无效的截止日期过滤: %s",
+	"todo.invalid_due":         "无效的截止日期，请使用 %s 或 %s。",
+	"todo.delete_editing":      "编辑任务时不能删除。",
+	"todo.entries_editing":     "编辑任务时不能编辑计时记录。",
+	"todo.empty":               "任务列表为空，请先添加任务。",
+	"todo.delete_title":        "删除任务",
+	"todo.delete_message":      "删除 \"%s\"?",
+	"todo.delete_ok":           "删除",
+	"todo.changed":             "任务已被修改，请重试。",
+	"todo.too_long":            "任务标题不能超过 %d 列，中文和 emoji 占两列。",
+	"todo.empty_title":         "任务标题为空，只输入了标签、优先级、截止日期或重复规则。",
+	"todo.save_failed":         "保存任务失败",
+	"todo.saved":               "任务已保存到文件: %s",
+	"todo.title_selected":      "· 已选 %d 项",
+	"todo.bulk_done":           "%s：%d 个任务。按 %s 撤销。",
+	"todo.bulk_delete_message": "删除选中的 %d 个任务？",
+	"todo.tag_title":           "设置标签",
+	"todo.tag_label":           "标签（-标签 表示删除）: ",
+	"todo.tag_empty":           "请至少输入一个标签。",
+	"todo.move_title":          "移动任务",
+	"todo.move_label":          "状态（%s）: ",
+	"todo.invalid_status":      "未知状态，可用的状态：%s",
+	"todo.nothing_to_undo":     "没有可以撤销的修改。",
+	"todo.undone":              "已撤销：%s",
+	"todo.bulk.add":            "添加任务",
+	"todo.bulk.edit":           "编辑任务",
+	"todo.bulk.due":            "设置截止日期",
+	"todo.bulk.complete":       "完成",
+	"todo.bulk.reopen":         "重新打开",
+	"todo.bulk.delete":         "删除",
+	"todo.bulk.tag":            "设置标签",
+	"todo.bulk.move":           "移动到 %s",
+	"todo.bulk.timer_start":    "开始计时",
+	"todo.bulk.timer_stop":     "停止计时",
+	"todo.bulk.entries":        "编辑计时记录",
+	"todo.bulk.note":           "编辑备注",
+
+	"todo.help.write":    "💡在上面的输入框中写下第一个待办任务。",
+	"todo.help.add":      "👏按回车把任务加入列表。",
+	"todo.help.edit":     "📝选中任务后按回车编辑。",
+	"todo.help.cancel":   "🤷按 Esc 取消编辑。",
+	"todo.help.delete":   "🥷按 Delete 删除选中的任务。",
+	"todo.help.complete": "✅按空格把任务标记为已完成。",
+	"todo.help.timer":    "⏱ 按 s 开始或停止任务计时。",
+	"todo.help.entries":  "🕒按 T 编辑任务的计时记录。",
+	"todo.help.due":      "📅按 d 设置任务的截止日期。",
+	"todo.help.quickadd": "🏷输入 #标签 !high @tomorrow 18:00 every week 设置标签、优先级、截止日期和重复规则。",
+	"todo.help.select":   "☑按 m 标记任务，v 选择范围，Ctrl+A 全选，然后一次处理多个任务。按 u 撤销。",
+
+	"action.todo-list.complete":   "标记任务为已完成或未完成",
+	"action.todo-list.timer":      "开始或停止任务计时",
+	"action.todo-list.entries":    "编辑任务的计时记录",
+	"action.todo-list.due":        "设置任务的截止日期",
+	"action.todo-list.delete":     "删除任务",
+	"action.todo-list.edit":       "编辑任务，或保存编辑",
+	"action.todo-list.cancel":     "取消编辑，或清除过滤",
+	"action.todo-list.input":      "聚焦输入框以添加任务",
+	"action.todo-list.note":       "编辑任务的备注",
+	"action.todo-list.mark":       "标记或取消标记任务",
+	"action.todo-list.range":      "开始或结束范围选择",
+	"action.todo-list.select-all": "选择列表中的所有任务",
+	"action.todo-list.tag":        "添加或删除选中任务的标签",
+	"action.todo-list.move":       "把选中的任务移到其他状态",
+	"action.todo-list.undo":       "撤销最近的修改",
+
+	"action.note.save":    "保存备注",
+	"action.note.preview": "在编辑和 Markdown 预览之间切换",
+	"action.note.editor":  "在 $EDITOR 中编辑备注",
+	"action.note.cancel":  "关闭备注编辑器",
+
+	"note.title":           "备注: %s",
+	"note.placeholder":     "用 Markdown 写备注: # 标题，- 列表，`代码`，**粗体**",
+	"note.mode_edit":       "编辑",
+	"note.mode_preview":    "预览",
+	"note.hint.save":       "保存",
+	"note.hint.preview":    "预览",
+	"note.hint.editor":     "$EDITOR",
+	"note.hint.cancel":     "关闭",
+	"note.editing":         "编辑任务时不能编辑备注。",
+	"note.editor_failed":   "编辑备注失败",
+	"note.discard_title":   "放弃修改",
+	"note.discard_message": "备注有未保存的修改，要放弃吗?",
+	"note.discard_ok":      "放弃",
+
+	"entries.title":            "计时记录: %s",
+	"entries.start":            "#%d 开始",
+	"entries.end":              "#%d 结束",
+	"entries.add":              "添加",
+	"entries.save":             "保存",
+	"entries.invalid":          "无效的计时记录",
+	"entries.invalid_start":    "记录 #%d: 无效的开始时间，请使用 %s",
+	"entries.invalid_end":      "记录 #%d: 无效的结束时间，请使用 %s",
+	"entries.end_before_start": "记录 #%d: 结束时间早于开始时间",
+	"entries.one_running":      "只能有一条记录在计时",
+	"entries.remove_title":     "删除计时记录",
+	"entries.remove_message":   "删除 %d 条 \"%s\" 的计时记录?",
+	"entries.remove_ok":        "删除",
+
+	"board.title":       "看板",
+	"board.description": "看板页",
+	"board.wip_reached": "%q 已达到在制品上限 (%d)。",
+
+	"action.board.left":       "聚焦左边的列",
+	"action.board.right":      "聚焦右边的列",
+	"action.board.move-left":  "把卡片移到左边的列",
+	"action.board.move-right": "把卡片移到右边的列",
+
+	"calendar.title":        "日历",
+	"calendar.description":  "按截止日期查看任务",
+	"calendar.month_layout": "2006年1月",
+	"calendar.week_title":   "第%d周，%d年",
+	"calendar.today":        "今天",
+	"calendar.month_help":   "←→↑↓ 日  </> 月  w 周  t 今天  回车 打开",
+	"calendar.week_help":    "←→↑↓ 日  </> 周  m 月  t 今天  回车 打开",
+
+	"stats.name":        "统计",
+	"stats.description": "效率统计",
+	"stats.title":       "统计",
+	"stats.created":     "每天新建 (%d天)",
+	"stats.completed":   "每天完成 (%d天)",
+	"stats.tag_time":    "各标签用时 (%d天)",
+	"stats.summary": " 未完成 %s%d[-]   已过期 %s%d[-]   新建 %s%d[-]   已完成 %s%d[-]\n" +
+		" 连续 %s%d[-] 天   最长 %s%d[-] 天",
+}
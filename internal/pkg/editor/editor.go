@@ -0,0 +1,32 @@
+// Package editor 在用户的外部编辑器中编辑文件
+package editor
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Name 使用的编辑器: $VISUAL, 其次$EDITOR, 都没有时用vi
+func Name() string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if editor := strings.TrimSpace(os.Getenv(env)); editor != "" {
+			return editor
+		}
+	}
+	return "vi"
+}
+
+// Run 在编辑器中编辑path, 使用当前终端, 编辑器退出后返回
+func Run(path string) error {
+	editor := Name()
+	// 编辑器可以带参数, 如 "code --wait"
+	args := strings.Fields(editor)
+	cmd := exec.Command(args[0], append(args[1:], path)...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("run editor %q: %w", editor, err)
+	}
+	return nil
+}
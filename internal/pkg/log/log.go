@@ -23,7 +23,7 @@ type Config struct {
 const DefaultConfig = `log:
   level: debug
   addSource: true
-  filename: kongtools.log
+  filename: "" # default is $XDG_STATE_HOME/kongtools/kongtools.log
   maxSize: 10
   maxBackups: 3
   maxAge: 7
@@ -31,6 +31,9 @@ const DefaultConfig = `log:
   multiWriter: false
 `
 
+// level 日志级别, 运行中可以修改
+var level slog.LevelVar
+
 // InitLogger 初始化日志
 func InitLogger(cfg Config) {
 	var w io.Writer
@@ -50,11 +53,10 @@ func InitLogger(cfg Config) {
 		w = io.MultiWriter(os.Stdout, ll) // 同时写文件和屏幕 // !基本不需要
 	}
 
-	level := slog.LevelDebug
-	cobra.CheckErr(level.UnmarshalText([]byte(cfg.Level)))
+	cobra.CheckErr(SetLevel(cfg.Level))
 
 	logHandler := slog.NewJSONHandler(w, &slog.HandlerOptions{
-		Level:     level,
+		Level:     &level,
 		AddSource: cfg.AddSource,
 	})
 	slog.SetDefault(slog.New(logHandler))
@@ -65,3 +67,13 @@ func InitLogger(cfg Config) {
 	slog.Warn("warn log")
 	slog.Error("error log")
 }
+
+// SetLevel 修改日志级别, 如 debug, info, warn 或 error
+func SetLevel(name string) error {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(name)); err != nil {
+		return err
+	}
+	level.Set(l)
+	return nil
+}
@@ -0,0 +1,88 @@
+// Package xdg 按XDG基础目录规范查找配置、数据、状态和缓存目录
+package xdg
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// App 应用目录名
+const App = "kongtools"
+
+// ConfigDir 配置目录, 默认为 ~/.config/kongtools
+func ConfigDir() (string, error) {
+	return appDir("XDG_CONFIG_HOME", ".config")
+}
+
+// DataDir 数据目录, 默认为 ~/.local/share/kongtools
+func DataDir() (string, error) {
+	return appDir("XDG_DATA_HOME", filepath.Join(".local", "share"))
+}
+
+// StateDir 状态目录, 默认为 ~/.local/state/kongtools
+func StateDir() (string, error) {
+	return appDir("XDG_STATE_HOME", filepath.Join(".local", "state"))
+}
+
+// CacheDir 缓存目录, 默认为 ~/.cache/kongtools
+func CacheDir() (string, error) {
+	return appDir("XDG_CACHE_HOME", ".cache")
+}
+
+// appDir 环境变量env指定的目录, 未设置时为家目录下的fallback, 再加上应用目录名
+func appDir(env, fallback string) (string, error) {
+	// 规范要求忽略相对路径
+	if dir := os.Getenv(env); filepath.IsAbs(dir) {
+		return filepath.Join(dir, App), nil
+	}
+	// Windows没有XDG目录, 用系统的应用目录
+	if runtime.GOOS == "windows" {
+		dir, err := os.UserConfigDir()
+		if env == "XDG_CACHE_HOME" {
+			dir, err = os.UserCacheDir()
+		}
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(dir, App), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, fallback, App), nil
+}
+
+// Expand 展开路径开头的 ~ 和其中的环境变量, 如 $HOME 或 ${XDG_DATA_HOME}
+func Expand(path string) (string, error) {
+	path = os.ExpandEnv(path)
+	if path != "~" && !strings.HasPrefix(path, "~/") && !strings.HasPrefix(path, "~"+string(filepath.Separator)) {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, path[1:]), nil
+}
+
+// Resolve 展开path后, 把相对路径解析为base下的路径, 空路径保持为空
+func Resolve(path, base string) (string, error) {
+	if path == "" {
+		return "", nil
+	}
+	path, err := Expand(path)
+	if err != nil {
+		return "", err
+	}
+	if path == "" {
+		return "", errors.New("path is empty after expanding environment variables")
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(base, path)
+	}
+	return filepath.Clean(path), nil
+}
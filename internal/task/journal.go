@@ -0,0 +1,103 @@
+package task
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 变更记录的动作
+const (
+	ActionCreated   = "created"
+	ActionCompleted = "completed"
+	ActionReopened  = "reopened"
+	ActionMoved     = "moved"
+	ActionDeleted   = "deleted"
+	ActionRestored  = "restored" // 撤销删除, 任务重新出现
+	ActionReverted  = "reverted" // 撤销新建, 任务消失
+)
+
+// key 在变更记录中识别任务, 新建时间相同(如示例任务没有新建时间)时再按标题区分
+func (t Task) key() string {
+	if t.CreatedAt.IsZero() {
+		return "title:" + t.Title
+	}
+	return t.CreatedAt.UTC().Format(time.RFC3339Nano)
+}
+
+// Event 一条变更记录, Task为变更后的快照(删除时为删除前)
+type Event struct {
+	Time   time.Time
+	Action string
+	Task   Task
+}
+
+// Journal 任务变更日志, 每行一条JSON, 只追加
+type Journal struct {
+	path  string
+	mutex sync.Mutex
+}
+
+// NewJournal 新建
+func NewJournal(path string) *Journal {
+	return &Journal{path: path}
+}
+
+// JournalPath 任务文件对应的变更日志路径, 如 tasks.json -> tasks.journal.jsonl
+func JournalPath(tasksPath string) string {
+	return strings.TrimSuffix(tasksPath, filepath.Ext(tasksPath)) + ".journal.jsonl"
+}
+
+// Append 追加记录
+func (j *Journal) Append(events ...Event) error {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(j.path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(j.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	enc := json.NewEncoder(f)
+	for _, e := range events {
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Read 读取所有记录, 文件不存在时返回空, 损坏的行会被跳过
+func (j *Journal) Read() ([]Event, error) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	f, err := os.Open(j.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var events []Event
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		events = append(events, e)
+	}
+	return events, scanner.Err()
+}
@@ -0,0 +1,313 @@
+package task
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Priority 优先级
+type Priority int
+
+// 优先级, 零值表示未设置
+const (
+	PriorityNone Priority = iota
+	PriorityLow
+	PriorityMedium
+	PriorityHigh
+)
+
+// priorityNames 快速添加中优先级的写法
+var priorityNames = map[string]Priority{
+	"low": PriorityLow, "l": PriorityLow, "1": PriorityLow,
+	"medium": PriorityMedium, "med": PriorityMedium, "m": PriorityMedium, "2": PriorityMedium,
+	"high": PriorityHigh, "h": PriorityHigh, "3": PriorityHigh,
+}
+
+// String 优先级的名称
+func (p Priority) String() string {
+	switch p {
+	case PriorityLow:
+		return "low"
+	case PriorityMedium:
+		return "medium"
+	case PriorityHigh:
+		return "high"
+	}
+	return ""
+}
+
+// TokenKind 快速添加中识别出的标记类型
+type TokenKind int
+
+// 标记类型
+const (
+	TokenTag TokenKind = iota + 1
+	TokenPriority
+	TokenDue
+	TokenRecurrence
+)
+
+// Token 识别出的标记, Start和End为在原文中的字节位置
+type Token struct {
+	Kind  TokenKind
+	Start int
+	End   int
+}
+
+// QuickAdd 快速添加的解析结果
+type QuickAdd struct {
+	Title      string
+	Tags       []string
+	Priority   Priority
+	Due        *time.Time
+	Recurrence string
+	Tokens     []Token // 按位置排序
+}
+
+// Apply 把解析出的字段设置到任务
+func (q QuickAdd) Apply(t *Task) {
+	t.Title = q.Title
+	t.Tags = q.Tags
+	t.Priority = q.Priority
+	t.Due = q.Due
+	t.Recurrence = q.Recurrence
+}
+
+// word 以空白分隔的词和它在原文中的字节位置
+type word struct {
+	text       string
+	start, end int
+}
+
+func splitWords(text string) []word {
+	var words []word
+	start := -1
+	for i, r := range text {
+		if unicode.IsSpace(r) {
+			if start >= 0 {
+				words = append(words, word{text[start:i], start, i})
+				start = -1
+			}
+		} else if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		words = append(words, word{text[start:], start, len(text)})
+	}
+	return words
+}
+
+// ParseQuickAdd 解析新任务的快速添加语法, now用于计算相对日期:
+//
+//	#tag                   标签, 可以有多个
+//	!low !medium !high     优先级, 也可以写作 !l !m !h 或 !1 !2 !3
+//	@today @tomorrow @mon  到期日, 还支持 @2006-01-02 @01-02 @+3d @+2w, 后面可以跟时间 18:00
+//	every week             重复, 如 every day, every 2 weeks, every monday, every weekday
+//
+// 优先级, 到期日和重复只识别第一个, 其余的词留在标题中.
+func ParseQuickAdd(text string, now time.Time) QuickAdd {
+	var q QuickAdd
+	words := splitWords(text)
+	used := make([]bool, len(words))
+
+	for i := 0; i < len(words); i++ {
+		w := words[i].text
+		n := 0 // 标记占用的词数
+		kind := TokenKind(0)
+		switch {
+		case isTag(w):
+			tag := w[1:]
+			if !contains(q.Tags, tag) {
+				q.Tags = append(q.Tags, tag)
+			}
+			kind, n = TokenTag, 1
+		case strings.HasPrefix(w, "!") && q.Priority == PriorityNone:
+			if p, ok := priorityNames[strings.ToLower(w[1:])]; ok {
+				q.Priority = p
+				kind, n = TokenPriority, 1
+			}
+		case strings.HasPrefix(w, "@") && q.Due == nil:
+			var next string
+			if i+1 < len(words) {
+				next = words[i+1].text
+			}
+			if due, count, ok := parseDueWords(w[1:], next, now); ok {
+				q.Due = &due
+				kind, n = TokenDue, count
+			}
+		case strings.EqualFold(w, "every") && q.Recurrence == "":
+			var rest []string
+			for _, next := range words[i+1 : min(i+3, len(words))] {
+				rest = append(rest, next.text)
+			}
+			if recurrence, count, ok := parseRecurrence(rest); ok {
+				q.Recurrence = recurrence
+				kind, n = TokenRecurrence, count+1
+			}
+		}
+		if n == 0 {
+			continue
+		}
+		q.Tokens = append(q.Tokens, Token{Kind: kind, Start: words[i].start, End: words[i+n-1].end})
+		for j := i; j < i+n; j++ {
+			used[j] = true
+		}
+		i += n - 1
+	}
+
+	var title []string
+	for i, w := range words {
+		if !used[i] {
+			title = append(title, w.text)
+		}
+	}
+	q.Title = strings.Join(title, " ")
+	return q
+}
+
+// isTag 是否为标签, 如 #home, #work/project
+func isTag(w string) bool {
+	if len(w) < 2 || w[0] != '#' {
+		return false
+	}
+	for _, r := range w[1:] {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("-_/", r) {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// parseDueWords 解析 @ 之后的日期, next为下一个词, 是时间时一起解析.
+// 返回到期时间和占用的词数.
+func parseDueWords(date, next string, now time.Time) (time.Time, int, bool) {
+	now = now.Local()
+	if hour, minute, ok := parseClock(date); ok {
+		// 只有时间时为今天
+		y, m, d := now.Date()
+		return time.Date(y, m, d, hour, minute, 0, 0, time.Local), 1, true
+	}
+	day, ok := parseDate(strings.ToLower(date), now)
+	if !ok {
+		return time.Time{}, 0, false
+	}
+	if hour, minute, ok := parseClock(next); ok {
+		return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, time.Local), 2, true
+	}
+	return day, 1, true
+}
+
+// parseClock 解析 15:04
+func parseClock(s string) (int, int, bool) {
+	clock, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, 0, false
+	}
+	return clock.Hour(), clock.Minute(), true
+}
+
+// weekdayNames 星期的写法
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+// parseDate 解析日期, 返回当天零点
+func parseDate(s string, now time.Time) (time.Time, bool) {
+	y, m, d := now.Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+
+	switch s {
+	case "today":
+		return today, true
+	case "tomorrow", "tom":
+		return today.AddDate(0, 0, 1), true
+	}
+	if weekday, ok := weekdayNames[s]; ok {
+		// 下一个该星期的日期, 不包括今天
+		days := (int(weekday)-int(today.Weekday())+6)%7 + 1
+		return today.AddDate(0, 0, days), true
+	}
+	if strings.HasPrefix(s, "+") && len(s) > 2 {
+		n, err := strconv.Atoi(s[1 : len(s)-1])
+		if err != nil || n < 0 {
+			return time.Time{}, false
+		}
+		switch s[len(s)-1] {
+		case 'd':
+			return today.AddDate(0, 0, n), true
+		case 'w':
+			return today.AddDate(0, 0, 7*n), true
+		case 'm':
+			return today.AddDate(0, n, 0), true
+		}
+		return time.Time{}, false
+	}
+	if day, err := time.ParseInLocation(time.DateOnly, s, time.Local); err == nil {
+		return day, true
+	}
+	if day, err := time.ParseInLocation("01-02", s, time.Local); err == nil {
+		// 没有年份时为今天或之后最近的一天
+		day = time.Date(y, day.Month(), day.Day(), 0, 0, 0, 0, time.Local)
+		if day.Before(today) {
+			day = day.AddDate(1, 0, 0)
+		}
+		return day, true
+	}
+	return time.Time{}, false
+}
+
+// recurrenceUnits 重复的单位, 复数形式也可以
+var recurrenceUnits = map[string]string{
+	"day": "day", "days": "day",
+	"week": "week", "weeks": "week",
+	"month": "month", "months": "month",
+	"year": "year", "years": "year",
+}
+
+// parseRecurrence 解析 every 之后的词, 返回规范化的重复规则和占用的词数
+func parseRecurrence(words []string) (string, int, bool) {
+	if len(words) == 0 {
+		return "", 0, false
+	}
+	first := strings.ToLower(words[0])
+	if unit, ok := recurrenceUnits[first]; ok {
+		return "every " + unit, 1, true
+	}
+	if first == "weekday" || first == "weekdays" {
+		return "every weekday", 1, true
+	}
+	if weekday, ok := weekdayNames[first]; ok {
+		return "every " + strings.ToLower(weekday.String()), 1, true
+	}
+	n, err := strconv.Atoi(first)
+	if err != nil || n < 1 || len(words) < 2 {
+		return "", 0, false
+	}
+	unit, ok := recurrenceUnits[strings.ToLower(words[1])]
+	if !ok {
+		return "", 0, false
+	}
+	if n == 1 {
+		return "every " + unit, 2, true
+	}
+	return fmt.Sprintf("every %d %ss", n, unit), 2, true
+}
@@ -0,0 +1,218 @@
+package task
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParseQuickAdd(t *testing.T) {
+	// 2024-05-30 是星期四
+	now := time.Date(2024, 5, 30, 10, 0, 0, 0, time.Local)
+	date := func(y int, m time.Month, d, hour, minute int) *time.Time {
+		due := time.Date(y, m, d, hour, minute, 0, 0, time.Local)
+		return &due
+	}
+	type token struct {
+		kind TokenKind
+		text string // 原文中Start到End的内容
+	}
+
+	tests := []struct {
+		name       string
+		text       string
+		title      string
+		tags       []string
+		priority   Priority
+		due        *time.Time
+		recurrence string
+		tokens     []token
+	}{
+		{
+			name: "plain", text: "  buy   milk ", title: "buy milk",
+		},
+		{
+			name: "tags", text: "buy milk #home #shop/food #home", title: "buy milk",
+			tags:   []string{"home", "shop/food"},
+			tokens: []token{{TokenTag, "#home"}, {TokenTag, "#shop/food"}, {TokenTag, "#home"}},
+		},
+		{
+			name: "not a tag", text: "issue # #a.b", title: "issue # #a.b",
+		},
+		{
+			name: "high", text: "fix bug !high", title: "fix bug", priority: PriorityHigh,
+			tokens: []token{{TokenPriority, "!high"}},
+		},
+		{
+			name: "low short", text: "!L fix bug", title: "fix bug", priority: PriorityLow,
+			tokens: []token{{TokenPriority, "!L"}},
+		},
+		{
+			name: "invalid priority", text: "fix bug !urgent", title: "fix bug !urgent",
+		},
+		{
+			name: "only first priority", text: "fix bug !high !low", title: "fix bug !low", priority: PriorityHigh,
+			tokens: []token{{TokenPriority, "!high"}},
+		},
+		{
+			name: "today", text: "call mom @today", title: "call mom", due: date(2024, 5, 30, 0, 0),
+			tokens: []token{{TokenDue, "@today"}},
+		},
+		{
+			name: "today with time", text: "call mom @today 18:00", title: "call mom", due: date(2024, 5, 30, 18, 0),
+			tokens: []token{{TokenDue, "@today 18:00"}},
+		},
+		{
+			name: "tomorrow", text: "call mom @Tomorrow", title: "call mom", due: date(2024, 5, 31, 0, 0),
+			tokens: []token{{TokenDue, "@Tomorrow"}},
+		},
+		{
+			name: "tomorrow with time", text: "call mom @tomorrow 09:30 please", title: "call mom please", due: date(2024, 5, 31, 9, 30),
+			tokens: []token{{TokenDue, "@tomorrow 09:30"}},
+		},
+		{
+			name: "invalid time stays in title", text: "call mom @tomorrow 25:00", title: "call mom 25:00", due: date(2024, 5, 31, 0, 0),
+			tokens: []token{{TokenDue, "@tomorrow"}},
+		},
+		{
+			name: "only time", text: "standup @09:15", title: "standup", due: date(2024, 5, 30, 9, 15),
+			tokens: []token{{TokenDue, "@09:15"}},
+		},
+		{
+			name: "weekday", text: "review @thu", title: "review", due: date(2024, 6, 6, 0, 0),
+			tokens: []token{{TokenDue, "@thu"}},
+		},
+		{
+			name: "in days", text: "pay rent @+3d", title: "pay rent", due: date(2024, 6, 2, 0, 0),
+			tokens: []token{{TokenDue, "@+3d"}},
+		},
+		{
+			name: "in weeks", text: "pay rent @+2w", title: "pay rent", due: date(2024, 6, 13, 0, 0),
+			tokens: []token{{TokenDue, "@+2w"}},
+		},
+		{
+			name: "in months", text: "pay rent @+1m", title: "pay rent", due: date(2024, 6, 30, 0, 0),
+			tokens: []token{{TokenDue, "@+1m"}},
+		},
+		{
+			name: "invalid offset", text: "pay rent @+3y @+d", title: "pay rent @+3y @+d",
+		},
+		{
+			name: "full date", text: "renew @2024-07-01", title: "renew", due: date(2024, 7, 1, 0, 0),
+			tokens: []token{{TokenDue, "@2024-07-01"}},
+		},
+		{
+			name: "month day later this year", text: "renew @06-01", title: "renew", due: date(2024, 6, 1, 0, 0),
+			tokens: []token{{TokenDue, "@06-01"}},
+		},
+		{
+			name: "month day today", text: "renew @05-30", title: "renew", due: date(2024, 5, 30, 0, 0),
+			tokens: []token{{TokenDue, "@05-30"}},
+		},
+		{
+			name: "month day rolls into next year", text: "renew @05-01", title: "renew", due: date(2025, 5, 1, 0, 0),
+			tokens: []token{{TokenDue, "@05-01"}},
+		},
+		{
+			name: "only first due", text: "renew @today @tomorrow", title: "renew @tomorrow", due: date(2024, 5, 30, 0, 0),
+			tokens: []token{{TokenDue, "@today"}},
+		},
+		{
+			name: "every day", text: "water plants every day", title: "water plants", recurrence: "every day",
+			tokens: []token{{TokenRecurrence, "every day"}},
+		},
+		{
+			name: "every week", text: "Every Week clean", title: "clean", recurrence: "every week",
+			tokens: []token{{TokenRecurrence, "Every Week"}},
+		},
+		{
+			name: "every 2 weeks", text: "payroll every 2 weeks", title: "payroll", recurrence: "every 2 weeks",
+			tokens: []token{{TokenRecurrence, "every 2 weeks"}},
+		},
+		{
+			name: "every 1 month", text: "payroll every 1 month", title: "payroll", recurrence: "every month",
+			tokens: []token{{TokenRecurrence, "every 1 month"}},
+		},
+		{
+			name: "every weekday", text: "standup every weekday", title: "standup", recurrence: "every weekday",
+			tokens: []token{{TokenRecurrence, "every weekday"}},
+		},
+		{
+			name: "every monday", text: "standup every mon", title: "standup", recurrence: "every monday",
+			tokens: []token{{TokenRecurrence, "every mon"}},
+		},
+		{
+			name: "not a recurrence", text: "read every book", title: "read every book",
+		},
+		{
+			name: "every at end", text: "read every", title: "read every",
+		},
+		{
+			name: "tokens in the middle", text: "call #family mom @tomorrow 18:00 about !h dinner", title: "call mom about dinner",
+			tags: []string{"family"}, priority: PriorityHigh, due: date(2024, 5, 31, 18, 0),
+			tokens: []token{{TokenTag, "#family"}, {TokenDue, "@tomorrow 18:00"}, {TokenPriority, "!h"}},
+		},
+		{
+			name: "only tokens", text: "#home !high @tomorrow 18:00 every week", title: "",
+			tags: []string{"home"}, priority: PriorityHigh, due: date(2024, 5, 31, 18, 0), recurrence: "every week",
+			tokens: []token{{TokenTag, "#home"}, {TokenPriority, "!high"}, {TokenDue, "@tomorrow 18:00"}, {TokenRecurrence, "every week"}},
+		},
+		{
+			name: "cjk byte positions", text: "买牛奶 #家 @明天", title: "买牛奶 @明天",
+			tags:   []string{"家"},
+			tokens: []token{{TokenTag, "#家"}},
+		},
+		{
+			name: "empty", text: "", title: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := ParseQuickAdd(tt.text, now)
+			if q.Title != tt.title {
+				t.Errorf("title = %q, want %q", q.Title, tt.title)
+			}
+			if !slices.Equal(q.Tags, tt.tags) {
+				t.Errorf("tags = %q, want %q", q.Tags, tt.tags)
+			}
+			if q.Priority != tt.priority {
+				t.Errorf("priority = %v, want %v", q.Priority, tt.priority)
+			}
+			switch {
+			case q.Due == nil && tt.due != nil:
+				t.Errorf("due = nil, want %s", tt.due)
+			case q.Due != nil && (tt.due == nil || !q.Due.Equal(*tt.due)):
+				t.Errorf("due = %s, want %v", q.Due, tt.due)
+			}
+			if q.Recurrence != tt.recurrence {
+				t.Errorf("recurrence = %q, want %q", q.Recurrence, tt.recurrence)
+			}
+
+			// 高亮用的位置
+			if len(q.Tokens) != len(tt.tokens) {
+				t.Fatalf("got %d tokens, want %d: %+v", len(q.Tokens), len(tt.tokens), q.Tokens)
+			}
+			for i, tok := range q.Tokens {
+				if i > 0 && tok.Start < q.Tokens[i-1].End {
+					t.Errorf("token %d starts at %d, before the previous one ends", i, tok.Start)
+				}
+				if tok.Start < 0 || tok.End > len(tt.text) || tok.Start >= tok.End {
+					t.Fatalf("token %d range [%d, %d) out of %q", i, tok.Start, tok.End, tt.text)
+				}
+				if got := tt.text[tok.Start:tok.End]; tok.Kind != tt.tokens[i].kind || got != tt.tokens[i].text {
+					t.Errorf("token %d = %d %q, want %d %q", i, tok.Kind, got, tt.tokens[i].kind, tt.tokens[i].text)
+				}
+			}
+		})
+	}
+}
+
+func TestQuickAddApply(t *testing.T) {
+	now := time.Date(2024, 5, 30, 10, 0, 0, 0, time.Local)
+	var item Task
+	ParseQuickAdd("call mom #family !m @tomorrow every week", now).Apply(&item)
+	if item.Title != "call mom" || !slices.Equal(item.Tags, []string{"family"}) || item.Priority != PriorityMedium ||
+		item.Due == nil || item.Recurrence != "every week" {
+		t.Fatalf("task = %+v", item)
+	}
+}
@@ -0,0 +1,96 @@
+package task
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// 报表分组方式
+const (
+	GroupByTag  = "tag"
+	GroupByTask = "task"
+	GroupByDay  = "day"
+)
+
+// Untagged 无标签任务的分组名
+const Untagged = "(untagged)"
+
+// ReportRow 报表行
+type ReportRow struct {
+	Group    string        `json:"group"`
+	Duration time.Duration `json:"-"`
+	Seconds  int64         `json:"seconds"`
+	Hours    float64       `json:"hours"`
+}
+
+// Report 统计[since, until)区间内的计时, since/until为零值表示不限制.
+// 按tag分组时, 多标签任务的时长会计入每个标签, total不重复计算.
+func Report(tasks []Task, since, until time.Time, groupBy string, now time.Time) (rows []ReportRow, total time.Duration, err error) {
+	totals := map[string]time.Duration{}
+
+	for _, t := range tasks {
+		for _, e := range t.Entries {
+			start, end := clip(e, since, until, now)
+			if !end.After(start) {
+				continue
+			}
+			total += end.Sub(start)
+
+			switch groupBy {
+			case GroupByTag:
+				tags := t.Tags
+				if len(tags) == 0 {
+					tags = []string{Untagged}
+				}
+				for _, tag := range tags {
+					totals[tag] += end.Sub(start)
+				}
+			case GroupByTask:
+				totals[t.Title] += end.Sub(start)
+			case GroupByDay:
+				// 跨天的记录按天拆分
+				for start.Before(end) {
+					y, m, d := start.Date()
+					next := time.Date(y, m, d+1, 0, 0, 0, 0, start.Location())
+					if next.After(end) {
+						next = end
+					}
+					totals[start.Format(time.DateOnly)] += next.Sub(start)
+					start = next
+				}
+			default:
+				return nil, 0, fmt.Errorf("unknown group by: %q", groupBy)
+			}
+		}
+	}
+
+	rows = make([]ReportRow, 0, len(totals))
+	for group, d := range totals {
+		rows = append(rows, ReportRow{
+			Group:    group,
+			Duration: d,
+			Seconds:  int64(d / time.Second),
+			Hours:    d.Hours(),
+		})
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i].Group < rows[j].Group
+	})
+	return rows, total, nil
+}
+
+// clip 把记录裁剪到[since, until)区间
+func clip(e TimeEntry, since, until, now time.Time) (start, end time.Time) {
+	start, end = e.Start, e.End
+	if e.Running() {
+		end = now
+	}
+	if !since.IsZero() && start.Before(since) {
+		start = since
+	}
+	if !until.IsZero() && end.After(until) {
+		end = until
+	}
+	return
+}
@@ -0,0 +1,109 @@
+package task
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReport(t *testing.T) {
+	day := func(d, h, m int) time.Time { return time.Date(2024, 5, d, h, m, 0, 0, time.UTC) }
+	entry := func(start, end time.Time) TimeEntry { return TimeEntry{Start: start, End: end} }
+	now := day(3, 12, 0)
+	since, until := day(2, 0, 0), day(3, 0, 0)
+
+	tasks := []Task{
+		// 完全在区间内
+		{Title: "inside", Tags: []string{"work"}, Entries: []TimeEntry{entry(day(2, 9, 0), day(2, 10, 0))}},
+		// 跨过since, 只算since之后
+		{Title: "across since", Tags: []string{"work", "client"}, Entries: []TimeEntry{entry(day(1, 23, 0), day(2, 0, 30))}},
+		// 跨过until, 只算until之前
+		{Title: "across until", Entries: []TimeEntry{entry(day(2, 23, 30), day(3, 1, 0))}},
+		// 区间外
+		{Title: "before", Tags: []string{"work"}, Entries: []TimeEntry{entry(day(1, 9, 0), day(2, 0, 0))}},
+		{Title: "after", Tags: []string{"work"}, Entries: []TimeEntry{entry(day(3, 0, 0), day(3, 2, 0))}},
+		// 计时中, 算到now再裁剪
+		{Title: "running", Tags: []string{"client"}, Entries: []TimeEntry{{Start: day(2, 22, 0)}}},
+	}
+
+	tests := []struct {
+		name         string
+		since, until time.Time
+		groupBy      string
+		want         map[string]time.Duration
+		total        time.Duration
+	}{
+		{
+			name: "by tag", since: since, until: until, groupBy: GroupByTag,
+			want: map[string]time.Duration{
+				"work":   90 * time.Minute,
+				"client": 150 * time.Minute,
+				Untagged: 30 * time.Minute,
+			},
+			total: 4 * time.Hour,
+		},
+		{
+			name: "by task", since: since, until: until, groupBy: GroupByTask,
+			want: map[string]time.Duration{
+				"inside":       time.Hour,
+				"across since": 30 * time.Minute,
+				"across until": 30 * time.Minute,
+				"running":      2 * time.Hour,
+			},
+			total: 4 * time.Hour,
+		},
+		{
+			name: "by day without limits", groupBy: GroupByDay,
+			want: map[string]time.Duration{
+				"2024-05-01": 16 * time.Hour,
+				"2024-05-02": 4 * time.Hour,
+				"2024-05-03": 15 * time.Hour,
+			},
+			total: 35 * time.Hour,
+		},
+		{
+			name: "only until", until: day(2, 0, 0), groupBy: GroupByTask,
+			want: map[string]time.Duration{
+				"across since": time.Hour,
+				"before":       15 * time.Hour,
+			},
+			total: 16 * time.Hour,
+		},
+		{
+			name: "empty range", since: until, until: since, groupBy: GroupByTag,
+			want: map[string]time.Duration{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, total, err := Report(tasks, tt.since, tt.until, tt.groupBy, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if total != tt.total {
+				t.Errorf("total = %s, want %s", total, tt.total)
+			}
+			if len(rows) != len(tt.want) {
+				t.Fatalf("got %d rows, want %d: %+v", len(rows), len(tt.want), rows)
+			}
+			for i, row := range rows {
+				if i > 0 && rows[i-1].Group >= row.Group {
+					t.Errorf("rows not sorted: %q before %q", rows[i-1].Group, row.Group)
+				}
+				want, ok := tt.want[row.Group]
+				if !ok || row.Duration != want {
+					t.Errorf("group %q = %s, want %s", row.Group, row.Duration, want)
+				}
+				if row.Seconds != int64(want/time.Second) || row.Hours != want.Hours() {
+					t.Errorf("group %q = %d seconds, %v hours, want %d, %v", row.Group, row.Seconds, row.Hours, int64(want/time.Second), want.Hours())
+				}
+			}
+		})
+	}
+}
+
+func TestReportUnknownGroup(t *testing.T) {
+	tasks := []Task{{Title: "a", Entries: []TimeEntry{{Start: time.Unix(0, 0), End: time.Unix(60, 0)}}}}
+	if _, _, err := Report(tasks, time.Time{}, time.Time{}, "week", time.Unix(60, 0)); err == nil {
+		t.Fatal("Report() = nil error for an unknown group")
+	}
+}
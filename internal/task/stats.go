@@ -0,0 +1,128 @@
+package task
+
+import (
+	"math"
+	"time"
+)
+
+// Stats 统计数据
+type Stats struct {
+	Days      []time.Time // 统计的每一天, 最后一天是今天
+	Created   []int       // 每天新建数
+	Completed []int       // 每天完成数
+
+	Streak        int // 当前连续完成天数
+	LongestStreak int // 最长连续完成天数
+	Open          int // 未完成数
+	Overdue       int // 过期数
+
+	TagTime []ReportRow // 统计区间内每个标签的计时
+}
+
+// ComputeStats 统计最近days天的数据.
+// 新建/完成数来自现有任务的时间戳, 加上变更日志中已删除且没有撤销的任务的时间戳.
+func ComputeStats(tasks []Task, events []Event, days int, now time.Time) Stats {
+	today := startOfDay(now)
+	first := today.AddDate(0, 0, -(days - 1))
+
+	s := Stats{
+		Days:      make([]time.Time, days),
+		Created:   make([]int, days),
+		Completed: make([]int, days),
+	}
+	for i := range s.Days {
+		s.Days[i] = first.AddDate(0, 0, i)
+	}
+
+	// 撤销删除后恢复的任务不再算作已删除
+	deleted := map[string][]Task{}
+	for _, e := range events {
+		key := e.Task.key()
+		switch e.Action {
+		case ActionDeleted:
+			deleted[key] = append(deleted[key], e.Task)
+		case ActionRestored:
+			if n := len(deleted[key]); n > 0 {
+				deleted[key] = deleted[key][:n-1]
+			}
+		}
+	}
+	history := append([]Task{}, tasks...)
+	for _, removed := range deleted {
+		history = append(history, removed...)
+	}
+
+	completedDays := map[time.Time]bool{}
+	for _, t := range history {
+		if i := dayIndex(first, t.CreatedAt, days); i >= 0 {
+			s.Created[i]++
+		}
+		if t.Completed && !t.CompletedAt.IsZero() {
+			completedDays[startOfDay(t.CompletedAt)] = true
+			if i := dayIndex(first, t.CompletedAt, days); i >= 0 {
+				s.Completed[i]++
+			}
+		}
+	}
+
+	for _, t := range tasks {
+		if !t.Completed {
+			s.Open++
+		}
+		if t.Overdue(now) {
+			s.Overdue++
+		}
+	}
+
+	s.Streak, s.LongestStreak = streaks(completedDays, today)
+	s.TagTime, _, _ = Report(tasks, first, time.Time{}, GroupByTag, now)
+	return s
+}
+
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Local().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+}
+
+// dayIndex t所在的天在统计区间中的序号, 不在区间内返回-1
+func dayIndex(first, t time.Time, days int) int {
+	if t.IsZero() {
+		return -1
+	}
+	day := startOfDay(t)
+	if day.Before(first) {
+		return -1
+	}
+	// 四舍五入, 避免夏令时导致的误差
+	i := int(math.Round(day.Sub(first).Hours() / 24))
+	if i >= days {
+		return -1
+	}
+	return i
+}
+
+// streaks 当前和最长的连续完成天数, 今天还没有完成时从昨天开始算
+func streaks(completedDays map[time.Time]bool, today time.Time) (current, longest int) {
+	day := today
+	if !completedDays[day] {
+		day = day.AddDate(0, 0, -1)
+	}
+	for completedDays[day] {
+		current++
+		day = day.AddDate(0, 0, -1)
+	}
+
+	for day := range completedDays {
+		if completedDays[day.AddDate(0, 0, -1)] {
+			continue // 不是连续的开始
+		}
+		n := 0
+		for d := day; completedDays[d]; d = d.AddDate(0, 0, 1) {
+			n++
+		}
+		if n > longest {
+			longest = n
+		}
+	}
+	return
+}
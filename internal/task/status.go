@@ -0,0 +1,73 @@
+package task
+
+import (
+	"time"
+)
+
+// DefaultStatuses 默认看板状态
+var DefaultStatuses = []string{"todo", "doing", "done"}
+
+// Workflow 看板状态流, 最后一个状态视为完成
+type Workflow struct {
+	Statuses []string
+}
+
+// NewWorkflow 新建, 状态少于两个时使用默认状态
+func NewWorkflow(statuses []string) Workflow {
+	if len(statuses) < 2 {
+		statuses = DefaultStatuses
+	}
+	return Workflow{Statuses: statuses}
+}
+
+// Initial 初始状态
+func (w Workflow) Initial() string {
+	return w.Statuses[0]
+}
+
+// Done 完成状态
+func (w Workflow) Done() string {
+	return w.Statuses[len(w.Statuses)-1]
+}
+
+// Index 状态的序号, 未知状态返回-1
+func (w Workflow) Index(status string) int {
+	for i, s := range w.Statuses {
+		if s == status {
+			return i
+		}
+	}
+	return -1
+}
+
+// Status 任务的状态, 兼容没有Status字段的旧数据
+func (w Workflow) Status(t Task) string {
+	if t.Completed {
+		return w.Done()
+	}
+	if w.Index(t.Status) < 0 || t.Status == w.Done() {
+		return w.Initial()
+	}
+	return t.Status
+}
+
+// SetStatus 设置状态, 同步完成标记和完成时间
+func (w Workflow) SetStatus(t *Task, status string) {
+	completed := status == w.Done()
+	if completed && !t.Completed {
+		t.CompletedAt = time.Now()
+	} else if !completed {
+		t.CompletedAt = time.Time{}
+	}
+	t.Status = status
+	t.Completed = completed
+}
+
+// SetCompleted 设置完成标记, 同步状态
+func (w Workflow) SetCompleted(t *Task, completed bool) {
+	if completed {
+		w.SetStatus(t, w.Done())
+	} else {
+		w.SetStatus(t, w.Initial())
+	}
+}
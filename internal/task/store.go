@@ -0,0 +1,271 @@
+package task
+
+import (
+	"os"
+	"sync"
+	"time"
+)
+
+// saveDelay 延迟保存的时间, 期间的修改合并为一次保存
+const saveDelay = 1 * time.Second
+
+// maxUndo 最多保留的撤销记录
+const maxUndo = 20
+
+// checkpoint 撤销记录: 修改前的任务列表和修改的描述
+type checkpoint struct {
+	label string
+	tasks []Task
+}
+
+// Store 任务存储, 由多个视图共享
+type Store struct {
+	path      string
+	tasks     []Task
+	journal   *Journal
+	listeners map[string]func()
+	undo      []checkpoint
+	saveTimer *time.Timer
+	mutex     sync.Mutex
+}
+
+// NewStore 新建
+func NewStore(path string) *Store {
+	return &Store{
+		path:      path,
+		tasks:     []Task{},
+		journal:   NewJournal(JournalPath(path)),
+		listeners: map[string]func(){},
+	}
+}
+
+// Path 保存路径
+func (s *Store) Path() string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.path
+}
+
+// SetPath 切换保存路径, 先保存未保存的修改. 新文件存在时读取其中的任务, 否则把当前任务保存到新文件
+func (s *Store) SetPath(path string) error {
+	if err := s.Flush(); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	s.path = path
+	s.journal = NewJournal(JournalPath(path))
+	s.mutex.Unlock()
+
+	if _, err := os.Stat(path); err == nil {
+		return s.Load()
+	}
+	return s.Save()
+}
+
+// Load 从文件读取任务
+func (s *Store) Load() error {
+	tasks, err := Load(s.Path())
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	s.tasks = tasks
+	s.undo = nil
+	s.mutex.Unlock()
+	s.notify("")
+	return nil
+}
+
+// Save 立即保存
+func (s *Store) Save() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return Save(s.path, s.tasks)
+}
+
+// ScheduleSave 延迟保存, done在保存后调用(在timer的goroutine中)
+func (s *Store) ScheduleSave(done func(error)) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.saveTimer != nil {
+		s.saveTimer.Stop()
+	}
+	s.saveTimer = time.AfterFunc(saveDelay, func() {
+		err := s.Save()
+		if done != nil {
+			done(err)
+		}
+	})
+}
+
+// Record 记录一条变更到变更日志
+func (s *Store) Record(action string, t Task) error {
+	return s.currentJournal().Append(Event{Time: time.Now(), Action: action, Task: t})
+}
+
+// Events 变更日志中的所有记录
+func (s *Store) Events() ([]Event, error) {
+	return s.currentJournal().Read()
+}
+
+// currentJournal 当前保存路径对应的变更日志
+func (s *Store) currentJournal() *Journal {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.journal
+}
+
+// Flush 取消延迟保存并立即保存, 没有待保存的修改时不处理
+func (s *Store) Flush() error {
+	s.mutex.Lock()
+	pending := s.saveTimer != nil && s.saveTimer.Stop()
+	s.saveTimer = nil
+	s.mutex.Unlock()
+
+	if !pending {
+		return nil
+	}
+	return s.Save()
+}
+
+// Len 任务数量
+func (s *Store) Len() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return len(s.tasks)
+}
+
+// Get 获取第index个任务
+func (s *Store) Get(index int) (Task, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if index < 0 || index >= len(s.tasks) {
+		return Task{}, false
+	}
+	return s.tasks[index], true
+}
+
+// Tasks 所有任务的副本
+func (s *Store) Tasks() []Task {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]Task{}, s.tasks...)
+}
+
+// Update 修改任务列表, fn返回新的列表.
+// 修改后通知除origin外的订阅者.
+func (s *Store) Update(origin string, fn func(tasks []Task) []Task) {
+	s.mutex.Lock()
+	s.tasks = fn(s.tasks)
+	s.mutex.Unlock()
+	s.notify(origin)
+}
+
+// Checkpoint 记录当前的任务列表, 之后的修改可以用Undo一次撤销, label描述修改
+func (s *Store) Checkpoint(label string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.undo = append(s.undo, checkpoint{label: label, tasks: cloneTasks(s.tasks)})
+	if len(s.undo) > maxUndo {
+		s.undo = s.undo[len(s.undo)-maxUndo:]
+	}
+}
+
+// Undo 恢复到最近的Checkpoint并通知所有订阅者, 返回撤销的修改描述.
+// 撤销引起的变化记入变更日志, 记录失败时仍然撤销并返回错误
+func (s *Store) Undo() (string, bool, error) {
+	s.mutex.Lock()
+	if len(s.undo) == 0 {
+		s.mutex.Unlock()
+		return "", false, nil
+	}
+	last := s.undo[len(s.undo)-1]
+	s.undo = s.undo[:len(s.undo)-1]
+	events := undoEvents(s.tasks, last.tasks, time.Now())
+	s.tasks = last.tasks
+	journal := s.journal
+	s.mutex.Unlock()
+	s.notify("")
+
+	if len(events) == 0 {
+		return last.label, true, nil
+	}
+	return last.label, true, journal.Append(events...)
+}
+
+// undoEvents 撤销时从before恢复到after的变更记录: 重新出现的任务、消失的任务、完成状态和看板列的变化
+func undoEvents(before, after []Task, now time.Time) []Event {
+	unmatched := map[string][]int{} // 键 -> before中未匹配的序号
+	for i, t := range before {
+		key := t.key()
+		unmatched[key] = append(unmatched[key], i)
+	}
+	matched := make([]bool, len(before))
+
+	var events []Event
+	for _, t := range after {
+		key := t.key()
+		indexes := unmatched[key]
+		if len(indexes) == 0 {
+			events = append(events, Event{Time: now, Action: ActionRestored, Task: t})
+			continue
+		}
+		old := before[indexes[0]]
+		unmatched[key] = indexes[1:]
+		matched[indexes[0]] = true
+		switch {
+		case t.Completed && !old.Completed:
+			events = append(events, Event{Time: now, Action: ActionCompleted, Task: t})
+		case !t.Completed && old.Completed:
+			events = append(events, Event{Time: now, Action: ActionReopened, Task: t})
+		case t.Status != old.Status:
+			events = append(events, Event{Time: now, Action: ActionMoved, Task: t})
+		}
+	}
+	for i, t := range before {
+		if !matched[i] {
+			events = append(events, Event{Time: now, Action: ActionReverted, Task: t})
+		}
+	}
+	return events
+}
+
+// cloneTasks 深拷贝, 修改副本不影响原列表
+func cloneTasks(tasks []Task) []Task {
+	clone := make([]Task, len(tasks))
+	for i, t := range tasks {
+		t.Tags = append([]string(nil), t.Tags...)
+		t.Entries = append([]TimeEntry(nil), t.Entries...)
+		if t.Due != nil {
+			due := *t.Due
+			t.Due = &due
+		}
+		clone[i] = t
+	}
+	return clone
+}
+
+// Subscribe 订阅任务变化, name同时作为Update的origin
+func (s *Store) Subscribe(name string, fn func()) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.listeners[name] = fn
+}
+
+func (s *Store) notify(origin string) {
+	s.mutex.Lock()
+	listeners := make([]func(), 0, len(s.listeners))
+	for name, fn := range s.listeners {
+		if name != origin {
+			listeners = append(listeners, fn)
+		}
+	}
+	s.mutex.Unlock()
+
+	for _, fn := range listeners {
+		fn()
+	}
+}
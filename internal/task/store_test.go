@@ -0,0 +1,70 @@
+package task
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestUndoRecordsEvents(t *testing.T) {
+	now := time.Now()
+	milk := Task{Title: "buy milk", CreatedAt: now.Add(-2 * time.Hour)}
+	report := Task{Title: "write report", CreatedAt: now.Add(-time.Hour)}
+	s := NewStore(filepath.Join(t.TempDir(), "tasks.json"))
+	s.Update("", func([]Task) []Task { return []Task{milk, report} })
+
+	// 删除后撤销: 任务恢复, 不再算作已删除
+	s.Checkpoint("delete")
+	s.Update("", func(tasks []Task) []Task { return tasks[1:] })
+	if err := s.Record(ActionDeleted, milk); err != nil {
+		t.Fatal(err)
+	}
+	if label, ok, err := s.Undo(); !ok || err != nil || label != "delete" {
+		t.Fatalf("Undo() = %q, %v, %v, want delete, true, nil", label, ok, err)
+	}
+
+	// 完成后撤销: 记录重新打开
+	s.Checkpoint("complete")
+	s.Update("", func(tasks []Task) []Task {
+		tasks[1].Completed, tasks[1].CompletedAt = true, now
+		return tasks
+	})
+	if _, _, err := s.Undo(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 新建后撤销: 任务消失
+	added := Task{Title: "call mom", CreatedAt: now}
+	s.Checkpoint("add")
+	s.Update("", func(tasks []Task) []Task { return append(tasks, added) })
+	if _, _, err := s.Undo(); err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := s.Events()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct{ action, title string }{
+		{ActionDeleted, "buy milk"},
+		{ActionRestored, "buy milk"},
+		{ActionReopened, "write report"},
+		{ActionReverted, "call mom"},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("got %d events, want %d: %+v", len(events), len(want), events)
+	}
+	for i, w := range want {
+		if events[i].Action != w.action || events[i].Task.Title != w.title {
+			t.Errorf("event %d = %s %q, want %s %q", i, events[i].Action, events[i].Task.Title, w.action, w.title)
+		}
+	}
+
+	stats := ComputeStats(s.Tasks(), events, 2, now)
+	if created := stats.Created[0] + stats.Created[1]; created != 2 {
+		t.Errorf("created = %d, want 2", created)
+	}
+	if _, ok, _ := s.Undo(); ok {
+		t.Error("Undo() = true with no checkpoint left")
+	}
+}
@@ -0,0 +1,121 @@
+package task
+
+import (
+	"fmt"
+	"time"
+)
+
+// Task 任务
+type Task struct {
+	Title       string
+	Completed   bool
+	Status      string     `json:",omitempty"`
+	Tags        []string   `json:",omitempty"`
+	Priority    Priority   `json:",omitempty"`
+	Due         *time.Time `json:",omitempty"`
+	Recurrence  string     `json:",omitempty"` // 重复规则, 如 every week
+	CreatedAt   time.Time
+	CompletedAt time.Time
+	Entries     []TimeEntry `json:",omitempty"`
+	Note        string      `json:",omitempty"` // 备注, Markdown格式
+}
+
+// TimeEntry 计时记录, End为零值表示计时中
+type TimeEntry struct {
+	Start time.Time
+	End   time.Time `json:",omitempty"`
+}
+
+// Running 是否计时中
+func (e TimeEntry) Running() bool {
+	return e.End.IsZero()
+}
+
+// Duration 时长, 计时中的记录算到now
+func (e TimeEntry) Duration(now time.Time) time.Duration {
+	end := e.End
+	if e.Running() {
+		end = now
+	}
+	if end.Before(e.Start) {
+		return 0
+	}
+	return end.Sub(e.Start)
+}
+
+// Running 是否有计时中的记录
+func (t *Task) Running() bool {
+	for _, e := range t.Entries {
+		if e.Running() {
+			return true
+		}
+	}
+	return false
+}
+
+// StartTimer 开始计时, 已在计时则不处理
+func (t *Task) StartTimer(now time.Time) {
+	if t.Running() {
+		return
+	}
+	t.Entries = append(t.Entries, TimeEntry{Start: now})
+}
+
+// StopTimer 停止计时
+func (t *Task) StopTimer(now time.Time) {
+	for i := range t.Entries {
+		if t.Entries[i].Running() {
+			t.Entries[i].End = now
+		}
+	}
+}
+
+// TotalDuration 总时长
+func (t *Task) TotalDuration(now time.Time) time.Duration {
+	var total time.Duration
+	for _, e := range t.Entries {
+		total += e.Duration(now)
+	}
+	return total
+}
+
+// DueOn 是否在day当天到期
+func (t *Task) DueOn(day time.Time) bool {
+	if t.Due == nil {
+		return false
+	}
+	y1, m1, d1 := t.Due.Local().Date()
+	y2, m2, d2 := day.Local().Date()
+	return y1 == y2 && m1 == m2 && d1 == d2
+}
+
+// Overdue 是否已过期, 到期日在今天之前且未完成
+func (t *Task) Overdue(now time.Time) bool {
+	if t.Due == nil || t.Completed {
+		return false
+	}
+	y, m, d := now.Local().Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+	return t.Due.Before(today)
+}
+
+// StartTimer 开始计时tasks[index], 同一时间只允许一个计时, 其余的会被停止
+func StartTimer(tasks []Task, index int, now time.Time) {
+	for i := range tasks {
+		if i != index {
+			tasks[i].StopTimer(now)
+		}
+	}
+	tasks[index].StartTimer(now)
+}
+
+// FormatDuration 格式化时长, 如 1h05m
+func FormatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	h := d / time.Hour
+	m := (d - h*time.Hour) / time.Minute
+	if h > 0 {
+		return fmt.Sprintf("%dh%02dm", h, m)
+	}
+	return fmt.Sprintf("%dm", m)
+}
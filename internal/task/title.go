@@ -0,0 +1,31 @@
+package task
+
+import "github.com/rivo/uniseg"
+
+// TitleWidth 标题的显示宽度, 中文和大部分emoji占两列
+func TitleWidth(title string) int {
+	return uniseg.StringWidth(title)
+}
+
+// TruncateTitle 按字素截断标题, 显示宽度不超过maxWidth, 不会切开多字节字符或组合字符.
+// 返回截断后的标题和是否被截断, maxWidth不大于0时不限制.
+func TruncateTitle(title string, maxWidth int) (string, bool) {
+	if maxWidth <= 0 || uniseg.StringWidth(title) <= maxWidth {
+		return title, false
+	}
+
+	width, end := 0, 0
+	state := -1
+	rest := title
+	for len(rest) > 0 {
+		var cluster string
+		var w int
+		cluster, rest, w, state = uniseg.FirstGraphemeClusterInString(rest, state)
+		if width+w > maxWidth {
+			break
+		}
+		width += w
+		end += len(cluster)
+	}
+	return title[:end], true
+}
@@ -0,0 +1,73 @@
+package task
+
+import (
+	"testing"
+
+	"github.com/rivo/tview"
+)
+
+func TestTitleWidth(t *testing.T) {
+	tests := []struct {
+		name  string
+		title string
+		want  int
+	}{
+		{"empty", "", 0},
+		{"ascii", "buy milk", 8},
+		{"cjk", "买牛奶", 6},
+		{"mixed", "买milk", 6},
+		{"emoji", "✅done", 6},
+		{"zwj sequence", "\U0001F468\u200d\U0001F469\u200d\U0001F467", 2},
+		{"flag", "🇨🇳", 2},
+		{"combining mark", "cafe\u0301", 4},
+		{"tview tag is text", "[red]alert", 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TitleWidth(tt.title); got != tt.want {
+				t.Errorf("TitleWidth(%q) = %d, want %d", tt.title, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTruncateTitle(t *testing.T) {
+	tests := []struct {
+		name      string
+		title     string
+		maxWidth  int
+		want      string
+		truncated bool
+	}{
+		{"no limit", "buy milk", 0, "buy milk", false},
+		{"negative limit", "buy milk", -1, "buy milk", false},
+		{"fits", "buy milk", 8, "buy milk", false},
+		{"ascii", "buy milk", 3, "buy", true},
+		{"cjk at boundary", "买牛奶", 4, "买牛", true},
+		{"cjk across boundary", "买牛奶", 5, "买牛", true},
+		{"cjk too narrow", "买牛奶", 1, "", true},
+		{"mixed", "a买b", 2, "a", true},
+		{"emoji", "✅✅✅", 3, "✅", true},
+		{"zwj sequence kept whole", "\U0001F468\u200d\U0001F469\u200d\U0001F467\U0001F468\u200d\U0001F469\u200d\U0001F467", 3, "\U0001F468\u200d\U0001F469\u200d\U0001F467", true},
+		{"zwj sequence not split", "a\U0001F468\u200d\U0001F469\u200d\U0001F467", 2, "a", true},
+		{"flag not split", "🇨🇳🇯🇵", 3, "🇨🇳", true},
+		{"combining mark kept", "cafe\u0301s", 4, "cafe\u0301", true},
+		{"tview tag", "[red]alert", 5, "[red]", true},
+		{"open bracket", "[red]alert", 4, "[red", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, truncated := TruncateTitle(tt.title, tt.maxWidth)
+			if got != tt.want || truncated != tt.truncated {
+				t.Fatalf("TruncateTitle(%q, %d) = %q, %v, want %q, %v", tt.title, tt.maxWidth, got, truncated, tt.want, tt.truncated)
+			}
+			if tt.maxWidth > 0 && TitleWidth(got) > tt.maxWidth {
+				t.Errorf("width of %q is %d, more than %d", got, TitleWidth(got), tt.maxWidth)
+			}
+			// 显示前转义, 标签按原样显示, 宽度不变
+			if w := tview.TaggedStringWidth(tview.Escape(got)); w != TitleWidth(got) {
+				t.Errorf("escaped %q is %d wide, want %d", got, w, TitleWidth(got))
+			}
+		})
+	}
+}
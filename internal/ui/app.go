@@ -1,6 +1,13 @@
 package ui
 
 import (
+	"errors"
+	"fmt"
+	"kongtools/internal/i18n"
+	"slices"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 	"github.com/sagikazarmark/slog-shim"
 )
@@ -9,8 +16,26 @@ import (
 type App struct {
 	*tview.Application
 
-	Main  *Pages
-	views map[string]tview.Primitive
+	Main       *Pages
+	Content    *Pages
+	Registry   *Registry
+	Keymap     *Keymap
+	Themes     *Themes
+	Notifier   *Notifier
+	Background *Background
+	Navigator  *Navigator
+	Session    *Session
+	Layout     *Layout
+	views      map[string]tview.Primitive
+	themeSubs  map[string][]func() // 页面的主题订阅, 移除页面时取消
+
+	root      *tview.Flex
+	statusBar *StatusBar
+
+	palette  *Palette
+	overlays []overlayEntry
+	dialogs  int // 已创建的对话框数, 用于生成页面名
+	changed  func(name string)
 
 	logger *slog.Logger
 }
@@ -20,12 +45,27 @@ func NewApp(logger *slog.Logger) *App {
 	a := App{
 		Application: tview.NewApplication(),
 		Main:        NewPages(logger),
+		Content:     NewPages(logger),
+		Registry:    NewRegistry(logger),
+		Keymap:      NewKeymap(logger),
+		Themes:      NewThemes(logger),
+		Navigator:   &Navigator{},
+		Session:     NewSession(logger, ""),
 		logger:      logger.With("module", "ui-app"),
 	}
 
+	a.themeSubs = map[string][]func(){}
 	a.views = map[string]tview.Primitive{
 		"menu": NewMenu(logger),
 	}
+	a.Layout = NewLayout(logger, a.Menu(), a.Content)
+	a.palette = NewPalette(logger, a.runCommand, a.hidePalette)
+	a.Background = NewBackground(logger, a.Application)
+	a.Notifier = NewNotifier(logger, a.requestDraw)
+	a.statusBar = NewStatusBar(a.Notifier, a.statusHints)
+	a.root = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(a.Main, 0, 1, true).
+		AddItem(a.statusBar, 1, 0, false)
 	return &a
 }
 
@@ -35,7 +75,233 @@ func (a *App) Init() {
 	defer a.logger.Debug("init app end ...")
 	a.setupApp()
 
-	a.SetRoot(a.Main, true).EnableMouse(true)
+	a.SetRoot(a.root, true).EnableMouse(true)
+	a.SetAfterDrawFunc(a.drawToasts)
+}
+
+// Run 运行直到退出. 退出后UI goroutine不再执行QueueUpdateDraw, 停止后台任务, 之后的Post被忽略
+func (a *App) Run() error {
+	defer a.Background.Shutdown()
+	return a.Application.Run()
+}
+
+// Stop 退出应用, 可以在任意goroutine中调用.
+// 后台已提交的请求执行后才停止事件循环, 不会留下等待的goroutine.
+func (a *App) Stop() {
+	flushed := a.Background.stop()
+	go func() {
+		<-flushed
+		a.Application.Stop()
+	}()
+}
+
+// requestDraw 请求重绘, 可以在任意goroutine中调用
+func (a *App) requestDraw() {
+	a.Background.Post(nil)
+}
+
+// InitPages 初始化已注册的页面, 按注册顺序生成菜单和Content页面, home为初始页面, 可带参数.
+// 页面在Init中注册按键动作, 之后应用按键配置并检查冲突.
+func (a *App) InitPages(home string, keys KeymapConfig) error {
+	if err := a.InitActions(); err != nil {
+		return err
+	}
+	if err := a.Keymap.Apply(keys); err != nil {
+		return err
+	}
+	if err := a.Keymap.Check(); err != nil {
+		return err
+	}
+
+	a.buildMenu()
+	a.Menu().SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		return a.Keymap.Dispatch(ScopeMenu, event)
+	})
+
+	a.Session.Register("layout", layoutHook{app: a})
+	a.Session.Restore("layout")
+
+	route, err := ParseRoute(home)
+	if err != nil {
+		return err
+	}
+	if _, ok := a.Registry.Page(route.Name); !ok {
+		pages := a.Registry.Pages()
+		if len(pages) == 0 {
+			return fmt.Errorf("no page registered")
+		}
+		if route.Name != "" {
+			a.logger.Warn(fmt.Sprintf("unknown home page: %s.", route.Name))
+		}
+		route = Route{Name: pages[0].Info().Name}
+	}
+	a.Navigate(route.Name, route.Params)
+	// 页面参数会重置选中项等状态, 所以在应用参数之后恢复
+	for _, p := range a.Registry.Pages() {
+		a.Session.Restore(p.Info().Name)
+	}
+	// 启动页面不是上次的页面时, 指定的参数优先于保存的状态
+	if saved, err := ParseRoute(a.Session.Route()); (err != nil || !saved.equal(route)) && len(route.Params) > 0 {
+		if p, ok := a.Registry.Page(route.Name); ok {
+			if r, ok := p.(Routable); ok {
+				r.SetParams(route.Params)
+			}
+		}
+	}
+	return nil
+}
+
+// InitActions 初始化已注册的页面, 注册页面的按键动作以及切换、分屏和退出的动作, 不应用按键配置
+func (a *App) InitActions() error {
+	for _, p := range a.Registry.Pages() {
+		if err := a.initPage(p); err != nil {
+			return err
+		}
+	}
+	a.Keymap.Register(ScopeMenu, "quit", i18n.T("menu.quit"), a.quit, "q")
+	return nil
+}
+
+// initPage 初始化页面, 注册切换和分屏的动作, 并加入Content
+func (a *App) initPage(p Page) error {
+	info := p.Info()
+	if s, ok := p.(Stateful); ok {
+		a.Session.Register(info.Name, s)
+	}
+	if err := p.Init(); err != nil {
+		return fmt.Errorf("init page %s: %w", info.Name, err)
+	}
+
+	a.Keymap.Register(ScopeMenu, info.Name, i18n.T("menu.switch_to", info.Title), func() {
+		a.logger.Debug(fmt.Sprintf("switch to %s page ...", info.Name))
+		a.SwitchTo(info.Name)
+	}, string(info.Shortcut))
+	a.Keymap.Register(ScopeSplit, info.Name, i18n.T("split.open", info.Title), func() {
+		a.OpenSplit(info.Name)
+	})
+	a.Content.AddPage(info.Name, p.Primitive(), true, false)
+	return nil
+}
+
+// buildMenu 按注册顺序重建菜单, 快捷键来自按键配置
+func (a *App) buildMenu() {
+	current := a.Menu().GetCurrentItem()
+	a.Menu().Clear()
+	for _, p := range a.Registry.Pages() {
+		info := p.Info()
+		a.Menu().AddItem(info.Title, info.Description, a.Keymap.Shortcut(ScopeMenu, info.Name), func() {
+			a.Keymap.Run(ScopeMenu, info.Name)
+		})
+	}
+	a.Menu().AddItem(i18n.T("menu.quit"), i18n.T("menu.quit_hint"), a.Keymap.Shortcut(ScopeMenu, "quit"), a.quit)
+	a.Menu().SetCurrentItem(current)
+}
+
+// UpdatePages 按pages增删页面并按其顺序重建菜单: 关闭移除的页面, 初始化新的页面, 然后重新应用按键配置.
+// 当前页面被移除时切换到第一个页面.
+func (a *App) UpdatePages(pages []Page, keys KeymapConfig) error {
+	if len(pages) == 0 {
+		return fmt.Errorf("no page registered")
+	}
+	names := map[string]bool{}
+	for _, p := range pages {
+		names[p.Info().Name] = true
+	}
+	for _, p := range slices.Clone(a.Registry.Pages()) {
+		if !names[p.Info().Name] {
+			a.removePage(p)
+		}
+	}
+
+	var errs []error
+	for _, p := range pages {
+		name := p.Info().Name
+		if _, ok := a.Registry.Page(name); ok {
+			continue
+		}
+		if err := a.Registry.Register(p); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := a.initPage(p); err != nil {
+			errs = append(errs, err)
+			a.unsubscribeTheme(name)
+			a.Registry.Unregister(name)
+			continue
+		}
+		a.Session.Restore(name)
+	}
+	a.Registry.sort(pages)
+
+	if err := a.Keymap.Reload(keys); err != nil {
+		errs = append(errs, err)
+	}
+	a.buildMenu()
+
+	if _, ok := a.Registry.Page(a.Navigator.Current().Name); !ok && len(a.Registry.Pages()) > 0 {
+		a.SwitchTo(a.Registry.Pages()[0].Info().Name)
+	}
+	return errors.Join(errs...)
+}
+
+// removePage 关闭页面, 并移除它的菜单项、按键动作、主题订阅和会话状态
+func (a *App) removePage(p Page) {
+	name := p.Info().Name
+	if p.Primitive().HasFocus() {
+		a.SetFocus(a.Menu())
+	}
+	if a.Layout.Secondary() == name {
+		a.Layout.CloseSecondary()
+	}
+	p.Shutdown()
+	a.Content.RemovePage(name)
+	a.Keymap.Unregister(ScopeMenu, name)
+	a.Keymap.Unregister(ScopeSplit, name)
+	a.Keymap.Unregister(name, "")
+	a.Session.Unregister(name)
+	a.unsubscribeTheme(name)
+	a.Registry.Unregister(name)
+}
+
+// OnThemeChange 页面订阅主题变化, 移除页面时自动取消, 不会留住已移除的页面
+func (a *App) OnThemeChange(page string, fn func(*Theme)) {
+	a.themeSubs[page] = append(a.themeSubs[page], a.Themes.OnChange(fn))
+}
+
+// unsubscribeTheme 取消页面的主题订阅
+func (a *App) unsubscribeTheme(page string) {
+	for _, unsubscribe := range a.themeSubs[page] {
+		unsubscribe()
+	}
+	delete(a.themeSubs, page)
+}
+
+// ReloadKeys 重新应用按键配置并更新菜单的快捷键, 有错误时保留原来的按键
+func (a *App) ReloadKeys(keys KeymapConfig) error {
+	if err := a.Keymap.Reload(keys); err != nil {
+		return err
+	}
+	a.buildMenu()
+	return nil
+}
+
+// ShutdownPages 按注册的逆序关闭页面
+func (a *App) ShutdownPages() {
+	pages := a.Registry.Pages()
+	for i := len(pages) - 1; i >= 0; i-- {
+		pages[i].Shutdown()
+	}
+}
+
+// SwitchTo 不带参数切换到已注册的页面
+func (a *App) SwitchTo(name string) {
+	a.Navigate(name, nil)
+}
+
+// SetPageChangedFunc 设置切换页面后的回调
+func (a *App) SetPageChangedFunc(handler func(name string)) *App {
+	a.changed = handler
+	return a
 }
 
 func (a *App) setupApp() {
@@ -43,10 +309,73 @@ func (a *App) setupApp() {
 	a.setupStyles()
 }
 
+// ScopeMenu 菜单的按键作用域
+const ScopeMenu = "menu"
+
+// ScopeSplit 在分屏中打开页面的动作, 默认没有按键
+const ScopeSplit = "split"
+
 func (a *App) bindKeys() {
+	a.Keymap.Register(ScopeGlobal, "quit", i18n.T("action.global.quit"), a.quit, "Ctrl+Q")
+	a.Keymap.Register(ScopeGlobal, "focus-menu", i18n.T("action.global.focus-menu"), func() {
+		a.SetFocus(a.Menu())
+	}, "F2")
+	a.Keymap.Register(ScopeGlobal, "focus-content", i18n.T("action.global.focus-content"), func() {
+		a.SetFocus(a.Content)
+	}, "F3")
+	a.Keymap.Register(ScopeGlobal, "theme", i18n.T("action.global.theme"), a.Themes.Next, "F4")
+	a.Keymap.Register(ScopeGlobal, "palette", i18n.T("action.global.palette"), a.ShowPalette, "Ctrl+P")
+	a.Keymap.Register(ScopeGlobal, "messages", i18n.T("action.global.messages"), a.ShowMessages, "F5")
+	a.Keymap.Register(ScopeGlobal, "back", i18n.T("action.global.back"), a.Back, "Alt+Left")
+	a.Keymap.Register(ScopeGlobal, "forward", i18n.T("action.global.forward"), a.Forward, "Alt+Right")
+	a.Keymap.Register(ScopeGlobal, "sidebar", i18n.T("action.global.sidebar"), a.ToggleSidebar, "F6")
+	a.Keymap.Register(ScopeGlobal, "sidebar-shrink", i18n.T("action.global.sidebar-shrink"), func() {
+		a.Layout.ResizeSidebar(-sidebarStep)
+	}, "Alt+-")
+	a.Keymap.Register(ScopeGlobal, "sidebar-grow", i18n.T("action.global.sidebar-grow"), func() {
+		a.Layout.ResizeSidebar(sidebarStep)
+	}, "Alt+=")
+	a.Keymap.Register(ScopeGlobal, "split", i18n.T("action.global.split"), a.CycleSplit, "F7")
+	a.Keymap.Register(ScopeGlobal, "focus-pane", i18n.T("action.global.focus-pane"), a.FocusNextPane, "F8")
+
+	a.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		// 浮层显示时按键只交给浮层
+		if a.HasOverlay() {
+			return event
+		}
+		// 输入文本时不处理无修饰键的字符
+		if event.Key() == tcell.KeyRune && event.Modifiers()&(tcell.ModCtrl|tcell.ModAlt) == 0 && IsTextInput(a.GetFocus()) {
+			return event
+		}
+		return a.Keymap.Dispatch(ScopeGlobal, event)
+	})
+}
+
+func (a *App) quit() {
+	a.logger.Debug("quit app ...")
+	a.Stop()
 }
 
 func (a *App) setupStyles() {
+	a.Themes.OnChange(a.Main.ApplyTheme)
+	a.Themes.OnChange(a.Content.ApplyTheme)
+	a.Themes.OnChange(a.Menu().ApplyTheme)
+	a.Themes.OnChange(a.palette.ApplyTheme)
+	a.Themes.OnChange(a.statusBar.ApplyTheme)
+	a.Themes.OnChange(a.Layout.ApplyTheme)
+}
+
+// InitTheme 读取用户主题并切换到配置的主题, 有问题的主题文件只提示
+func (a *App) InitTheme(cfg ThemeConfig) error {
+	if err := a.Themes.Load(cfg.Dir); err != nil {
+		// 通知只显示一行
+		a.Notify(SeverityWarning, i18n.T("app.theme_skipped", strings.ReplaceAll(err.Error(), "\n", "; ")))
+	}
+	name := cfg.Name
+	if name == "" {
+		name = a.Themes.Current().Name
+	}
+	return a.Themes.Use(name)
 }
 
 // Views Views
@@ -0,0 +1,151 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rivo/tview"
+	"github.com/sagikazarmark/slog-shim"
+)
+
+// Background 后台任务, 结果通过QueueUpdateDraw交给UI goroutine.
+// 控件只能在UI goroutine中修改, 后台goroutine用Post修改控件.
+type Background struct {
+	app *tview.Application
+
+	mutex   sync.Mutex
+	queue   []func()      // 等待在UI goroutine中执行的函数
+	pumping bool          // 是否已请求执行queue
+	flushed chan struct{} // 最近一次请求执行后关闭
+	stopped bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	jobs   sync.WaitGroup
+
+	logger *slog.Logger
+}
+
+// NewBackground 新建
+func NewBackground(logger *slog.Logger, app *tview.Application) *Background {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Background{
+		app:    app,
+		ctx:    ctx,
+		cancel: cancel,
+		logger: logger.With("module", "ui-background"),
+	}
+}
+
+// Post 在UI goroutine中执行f并重绘, 可以在任意goroutine (包括UI goroutine) 中调用, 不会阻塞.
+// 按调用顺序执行, 关闭后忽略. f为nil时只重绘.
+func (b *Background) Post(f func()) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.stopped {
+		return
+	}
+	if f != nil {
+		b.queue = append(b.queue, f)
+	}
+	if b.pumping {
+		return
+	}
+	b.pumping = true
+	flushed := make(chan struct{})
+	b.flushed = flushed
+	// QueueUpdateDraw会等待执行完成, 在UI goroutine中直接调用会死锁
+	go func() {
+		b.app.QueueUpdateDraw(b.flush)
+		close(flushed)
+	}()
+}
+
+// flush 在UI goroutine中执行等待的函数
+func (b *Background) flush() {
+	b.mutex.Lock()
+	queue := b.queue
+	b.queue = nil
+	b.pumping = false
+	b.mutex.Unlock()
+
+	for _, f := range queue {
+		f()
+	}
+}
+
+// Go 在后台goroutine中执行work, 完成后在UI goroutine中调用done.
+// 关闭时ctx被取消, done不再调用.
+func (b *Background) Go(name string, work func(ctx context.Context) error, done func(err error)) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.stopped {
+		return
+	}
+
+	b.jobs.Add(1)
+	go func() {
+		defer b.jobs.Done()
+		err := work(b.ctx)
+		if err != nil {
+			b.logger.Warn(fmt.Sprintf("job %s failed: %s.", name, err))
+		}
+		if done != nil {
+			b.Post(func() { done(err) })
+		}
+	}()
+}
+
+// Every 每隔interval在UI goroutine中执行f, 返回停止函数
+func (b *Background) Every(interval time.Duration, f func()) (stop func()) {
+	ctx, cancel := context.WithCancel(b.ctx)
+	b.Go("ticker", func(context.Context) error {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				b.Post(f)
+			case <-ctx.Done():
+				return nil
+			}
+		}
+	}, nil)
+	return cancel
+}
+
+// After 在d之后在UI goroutine中执行f, 返回取消函数
+func (b *Background) After(d time.Duration, f func()) (cancel func()) {
+	timer := time.AfterFunc(d, func() {
+		b.Post(f)
+	})
+	return func() { timer.Stop() }
+}
+
+// Done 返回Shutdown时关闭的channel
+func (b *Background) Done() <-chan struct{} {
+	return b.ctx.Done()
+}
+
+// stop 之后的Post和Go被忽略, 返回的channel在已提交的请求执行后关闭.
+// 应用退出后不再执行请求, 等待中的goroutine无法结束, 所以要等它关闭后才能退出应用.
+func (b *Background) stop() <-chan struct{} {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.stopped = true
+	b.queue = nil
+	if b.flushed == nil {
+		b.flushed = make(chan struct{})
+		close(b.flushed)
+	}
+	return b.flushed
+}
+
+// Shutdown 停止所有后台任务并等待结束, 之后的Post和Go被忽略. 可以多次调用
+func (b *Background) Shutdown() {
+	b.stop()
+	b.cancel()
+	b.jobs.Wait()
+}
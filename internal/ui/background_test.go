@@ -0,0 +1,278 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/sagikazarmark/slog-shim"
+)
+
+const waitTimeout = 5 * time.Second
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+// runApp 在模拟屏幕上运行应用, 第一次绘制后返回, 测试结束时退出
+func runApp(t *testing.T) *tview.Application {
+	t.Helper()
+	app := tview.NewApplication().SetScreen(tcell.NewSimulationScreen("UTF-8"))
+	app.SetRoot(tview.NewBox(), true)
+	drawn := make(chan struct{})
+	var once sync.Once
+	app.SetAfterDrawFunc(func(tcell.Screen) { once.Do(func() { close(drawn) }) })
+
+	done := make(chan error, 1)
+	go func() { done <- app.Run() }()
+	t.Cleanup(func() {
+		app.Stop()
+		if err := <-done; err != nil {
+			t.Errorf("run app: %s", err)
+		}
+	})
+	wait(t, drawn, "first draw")
+	return app
+}
+
+func newBackground(t *testing.T) *Background {
+	t.Helper()
+	b := NewBackground(testLogger(), runApp(t))
+	// 应用退出前等待提交的请求执行完
+	t.Cleanup(func() {
+		wait(t, b.stop(), "in-flight post")
+		b.Shutdown()
+	})
+	return b
+}
+
+func wait(t *testing.T, ch <-chan struct{}, what string) {
+	t.Helper()
+	select {
+	case <-ch:
+	case <-time.After(waitTimeout):
+		t.Fatalf("timed out waiting for %s", what)
+	}
+}
+
+func TestPostKeepsOrder(t *testing.T) {
+	b := newBackground(t)
+	const goroutines, posts = 8, 200
+
+	// 只在UI goroutine中修改, 全部执行后才读取
+	got := make([][]int, goroutines)
+	var count atomic.Int64
+	done := make(chan struct{})
+
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < posts; i++ {
+				i := i
+				b.Post(func() {
+					got[g] = append(got[g], i)
+					if count.Add(1) == goroutines*posts {
+						close(done)
+					}
+				})
+			}
+		}(g)
+	}
+	wg.Wait()
+	wait(t, done, "posted functions")
+
+	for g, seq := range got {
+		if len(seq) != posts {
+			t.Fatalf("goroutine %d: got %d calls, want %d", g, len(seq), posts)
+		}
+		for i, v := range seq {
+			if v != i {
+				t.Fatalf("goroutine %d: call %d ran as %d, want posting order", g, v, i)
+			}
+		}
+	}
+}
+
+func TestPostFromUIGoroutine(t *testing.T) {
+	b := newBackground(t)
+	done := make(chan struct{})
+	var order []string
+	b.Post(func() {
+		order = append(order, "outer")
+		// 在UI goroutine中调用不能死锁, 排在当前函数之后执行
+		b.Post(func() {
+			order = append(order, "inner")
+			close(done)
+		})
+		order = append(order, "outer end")
+	})
+	wait(t, done, "nested post")
+	if want := []string{"outer", "outer end", "inner"}; !slices.Equal(order, want) {
+		t.Fatalf("order = %v, want %v", order, want)
+	}
+}
+
+func TestGoCallsDone(t *testing.T) {
+	b := newBackground(t)
+	failed := errors.New("failed")
+
+	for _, want := range []error{nil, failed} {
+		done := make(chan struct{})
+		var got error
+		b.Go("test", func(ctx context.Context) error {
+			return want
+		}, func(err error) {
+			got = err
+			close(done)
+		})
+		wait(t, done, "done callback")
+		if got != want {
+			t.Fatalf("done got %v, want %v", got, want)
+		}
+	}
+}
+
+func TestGoCancelledByShutdown(t *testing.T) {
+	b := NewBackground(testLogger(), runApp(t))
+	started := make(chan struct{})
+	var cancelled, called atomic.Bool
+	b.Go("test", func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		cancelled.Store(true)
+		return ctx.Err()
+	}, func(error) {
+		called.Store(true)
+	})
+	wait(t, started, "job start")
+
+	b.Shutdown()
+	if !cancelled.Load() {
+		t.Fatal("Shutdown returned before the job ended")
+	}
+	time.Sleep(50 * time.Millisecond)
+	if called.Load() {
+		t.Fatal("done called after Shutdown")
+	}
+}
+
+func TestEveryStops(t *testing.T) {
+	b := newBackground(t)
+	var count atomic.Int64
+	ticked := make(chan struct{})
+	var once sync.Once
+	stop := b.Every(5*time.Millisecond, func() {
+		if count.Add(1) == 3 {
+			once.Do(func() { close(ticked) })
+		}
+	})
+	wait(t, ticked, "three ticks")
+
+	stop()
+	// 停止前已经提交的一次可能还会执行
+	time.Sleep(20 * time.Millisecond)
+	stopped := count.Load()
+	time.Sleep(50 * time.Millisecond)
+	if got := count.Load(); got != stopped {
+		t.Fatalf("ran %d more times after stop", got-stopped)
+	}
+}
+
+func TestAfter(t *testing.T) {
+	b := newBackground(t)
+	done := make(chan struct{})
+	b.After(5*time.Millisecond, func() { close(done) })
+	wait(t, done, "After")
+
+	var called atomic.Bool
+	cancel := b.After(20*time.Millisecond, func() { called.Store(true) })
+	cancel()
+	time.Sleep(60 * time.Millisecond)
+	if called.Load() {
+		t.Fatal("After ran after cancel")
+	}
+}
+
+func TestShutdownIgnoresLaterCalls(t *testing.T) {
+	b := NewBackground(testLogger(), runApp(t))
+	b.Shutdown()
+	b.Shutdown()
+
+	var called atomic.Bool
+	b.Post(func() { called.Store(true) })
+	b.Go("test", func(context.Context) error {
+		called.Store(true)
+		return nil
+	}, nil)
+	b.After(time.Millisecond, func() { called.Store(true) })
+	time.Sleep(30 * time.Millisecond)
+	if called.Load() {
+		t.Fatal("function ran after Shutdown")
+	}
+}
+
+// startApp 在模拟屏幕上运行App, 第一次绘制后返回
+func startApp(t *testing.T) (*App, <-chan error) {
+	t.Helper()
+	a := NewApp(testLogger())
+	a.SetScreen(tcell.NewSimulationScreen("UTF-8"))
+	a.SetRoot(tview.NewBox(), true)
+	drawn := make(chan struct{})
+	var once sync.Once
+	a.SetAfterDrawFunc(func(tcell.Screen) { once.Do(func() { close(drawn) }) })
+
+	done := make(chan error, 1)
+	go func() { done <- a.Run() }()
+	wait(t, drawn, "first draw")
+	return a, done
+}
+
+func TestStopWaitsForPost(t *testing.T) {
+	a, done := startApp(t)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				a.Background.Post(nil)
+			}
+		}()
+	}
+	a.Stop()
+	wg.Wait()
+	if err := <-done; err != nil {
+		t.Fatalf("run app: %s", err)
+	}
+
+	// 退出前提交的请求都已执行, 没有goroutine在等待
+	a.Background.mutex.Lock()
+	flushed := a.Background.flushed
+	a.Background.mutex.Unlock()
+	wait(t, flushed, "in-flight post")
+}
+
+func TestPostAfterAppStops(t *testing.T) {
+	a, done := startApp(t)
+	a.Stop()
+	if err := <-done; err != nil {
+		t.Fatalf("run app: %s", err)
+	}
+
+	// 没有UI goroutine处理请求, 不能再提交
+	a.Background.Post(func() { t.Error("posted function ran after the app stopped") })
+	a.Background.mutex.Lock()
+	defer a.Background.mutex.Unlock()
+	if a.Background.pumping || len(a.Background.queue) > 0 {
+		t.Fatal("Post queued a request after the app stopped")
+	}
+}
@@ -0,0 +1,120 @@
+package ui
+
+import (
+	"strconv"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/sagikazarmark/slog-shim"
+)
+
+// barRunes 横向柱子不足一格的部分, 1/8到7/8
+var barRunes = []rune{'▏', '▎', '▍', '▌', '▋', '▊', '▉'}
+
+// Bar 柱状图的一项
+type Bar struct {
+	Label string
+	Value float64
+	Text  string // 显示在柱子后的文本, 为空时显示数值
+}
+
+// BarChart 横向柱状图, 每项一行
+type BarChart struct {
+	*tview.Box
+
+	bars  []Bar
+	color tcell.Color
+	theme *Theme
+
+	logger *slog.Logger
+}
+
+// NewBarChart 新建
+func NewBarChart(logger *slog.Logger) *BarChart {
+	return &BarChart{
+		Box:    tview.NewBox(),
+		color:  builtinThemes[0].Accent,
+		theme:  builtinThemes[0],
+		logger: logger.With("module", "ui-barchart"),
+	}
+}
+
+// SetBars 设置数据
+func (b *BarChart) SetBars(bars []Bar) *BarChart {
+	b.bars = bars
+	return b
+}
+
+// SetColor 设置柱子颜色
+func (b *BarChart) SetColor(color tcell.Color) *BarChart {
+	b.color = color
+	return b
+}
+
+// ApplyTheme 应用主题, 柱子颜色需要另外设置
+func (b *BarChart) ApplyTheme(theme *Theme) {
+	b.theme = theme
+	theme.ApplyBox(b.Box)
+}
+
+// Draw 绘制
+func (b *BarChart) Draw(screen tcell.Screen) {
+	b.Box.DrawForSubclass(screen, b)
+	x, y, width, height := b.GetInnerRect()
+	if width <= 0 || height <= 0 || len(b.bars) == 0 {
+		return
+	}
+
+	labelWidth, textWidth := 0, 0
+	max := 0.0
+	for _, bar := range b.bars {
+		labelWidth = maxInt(labelWidth, tview.TaggedStringWidth(bar.Label))
+		textWidth = maxInt(textWidth, tview.TaggedStringWidth(barText(bar)))
+		if bar.Value > max {
+			max = bar.Value
+		}
+	}
+	if labelWidth > width/3 {
+		labelWidth = width / 3
+	}
+	barWidth := width - labelWidth - textWidth - 2
+	if barWidth <= 0 || max == 0 {
+		barWidth, max = 0, 1
+	}
+
+	style := tcell.StyleDefault.Foreground(b.color).Background(b.GetBackgroundColor())
+	for i, bar := range b.bars {
+		if i >= height {
+			return
+		}
+		row := y + i
+		tview.Print(screen, bar.Label, x, row, labelWidth, tview.AlignRight, b.theme.Secondary)
+
+		// 以1/8格为单位
+		eighths := int(bar.Value / max * float64(barWidth*8))
+		col := x + labelWidth + 1
+		for ; eighths >= 8; eighths -= 8 {
+			screen.SetContent(col, row, '█', nil, style)
+			col++
+		}
+		if eighths > 0 {
+			screen.SetContent(col, row, barRunes[eighths-1], nil, style)
+			col++
+		}
+		tview.Print(screen, barText(bar), col+1, row, x+width-col-1, tview.AlignLeft, b.theme.Text)
+	}
+}
+
+func barText(bar Bar) string {
+	if bar.Text != "" {
+		return bar.Text
+	}
+	return strconv.FormatFloat(bar.Value, 'f', -1, 64)
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,364 @@
+package ui
+
+import (
+	"fmt"
+	"kongtools/internal/i18n"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/sagikazarmark/slog-shim"
+)
+
+// 日历显示模式
+const (
+	CalendarMonth = iota // 月视图
+	CalendarWeek         // 周日程
+)
+
+// Calendar 日历, 支持月视图和周日程
+type Calendar struct {
+	*tview.Box
+
+	mode     int
+	selected time.Time
+	now      func() time.Time
+	theme    *Theme
+
+	events   func(day time.Time) []string
+	selectFn func(day time.Time)
+	changeFn func(day time.Time)
+
+	logger *slog.Logger
+}
+
+// NewCalendar 新建
+func NewCalendar(logger *slog.Logger) *Calendar {
+	c := &Calendar{
+		Box:    tview.NewBox(),
+		mode:   CalendarMonth,
+		now:    time.Now,
+		theme:  builtinThemes[0],
+		logger: logger.With("module", "ui-calendar"),
+	}
+	c.selected = dateOf(c.now())
+
+	return c
+}
+
+// dateOf 日期, 去掉时分秒
+func dateOf(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// SetEventsFunc 设置每天的事件来源
+func (c *Calendar) SetEventsFunc(events func(day time.Time) []string) *Calendar {
+	c.events = events
+	return c
+}
+
+// SetSelectedFunc 设置在某天按Enter或双击时的回调
+func (c *Calendar) SetSelectedFunc(handler func(day time.Time)) *Calendar {
+	c.selectFn = handler
+	return c
+}
+
+// SetChangedFunc 设置选中日期变化时的回调
+func (c *Calendar) SetChangedFunc(handler func(day time.Time)) *Calendar {
+	c.changeFn = handler
+	return c
+}
+
+// ApplyTheme 应用主题
+func (c *Calendar) ApplyTheme(theme *Theme) {
+	c.theme = theme
+	theme.ApplyBox(c.Box)
+}
+
+// SetMode 设置显示模式
+func (c *Calendar) SetMode(mode int) *Calendar {
+	c.mode = mode
+	return c
+}
+
+// GetMode 显示模式
+func (c *Calendar) GetMode() int {
+	return c.mode
+}
+
+// SetDate 选中某天
+func (c *Calendar) SetDate(day time.Time) *Calendar {
+	day = dateOf(day)
+	if day.Equal(c.selected) {
+		return c
+	}
+	c.selected = day
+	if c.changeFn != nil {
+		c.changeFn(day)
+	}
+	return c
+}
+
+// GetDate 选中的日期
+func (c *Calendar) GetDate() time.Time {
+	return c.selected
+}
+
+func (c *Calendar) eventsOf(day time.Time) []string {
+	if c.events == nil {
+		return nil
+	}
+	return c.events(day)
+}
+
+// weekStart 所在周的周一
+func weekStart(day time.Time) time.Time {
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+// Draw 绘制
+func (c *Calendar) Draw(screen tcell.Screen) {
+	c.Box.DrawForSubclass(screen, c)
+	x, y, width, height := c.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+
+	if c.mode == CalendarWeek {
+		c.drawWeek(screen, x, y, width, height)
+	} else {
+		c.drawMonth(screen, x, y, width, height)
+	}
+}
+
+// drawMonth 月视图: 标题, 星期, 最多6周
+func (c *Calendar) drawMonth(screen tcell.Screen, x, y, width, height int) {
+	today := dateOf(c.now())
+	title := "[::b]" + c.selected.Format(i18n.T("calendar.month_layout"))
+	tview.Print(screen, title, x, y, width, tview.AlignCenter, c.theme.Text)
+	c.drawHelp(screen, x, y, width, height, i18n.T("calendar.month_help"))
+
+	cellWidth := width / 7
+	if cellWidth < 3 || height < 3 {
+		return
+	}
+	cellHeight := c.cellHeight(height)
+
+	for i := 0; i < 7; i++ {
+		name := i18n.ShortWeekday(time.Weekday((i + 1) % 7))
+		tview.Print(screen, name, x+i*cellWidth, y+1, cellWidth, tview.AlignCenter, c.theme.Accent)
+	}
+
+	first := time.Date(c.selected.Year(), c.selected.Month(), 1, 0, 0, 0, 0, c.selected.Location())
+	day := weekStart(first)
+	for week := 0; week < 6; week++ {
+		for wd := 0; wd < 7; wd++ {
+			cx, cy := x+wd*cellWidth, y+2+week*cellHeight
+			if cy >= y+height-1 {
+				return
+			}
+
+			fg, bg := c.theme.Text, c.theme.Background
+			if day.Month() != c.selected.Month() {
+				fg = c.theme.Secondary
+			}
+			color := fg
+			if day.Equal(c.selected) {
+				fg, bg = bg, fg
+			}
+
+			label := fmt.Sprintf("%2d", day.Day())
+			if day.Equal(today) {
+				label = "*" + label
+			}
+			if n := len(c.eventsOf(day)); n > 0 {
+				label += fmt.Sprintf(" •%d", n)
+			}
+			printFilled(screen, tview.Escape(label), cx, cy, cellWidth-1, fg, bg)
+
+			// 有多余的行时显示事件
+			for i, event := range c.eventsOf(day) {
+				if i+1 >= cellHeight || cy+i+1 >= y+height-1 {
+					break
+				}
+				tview.Print(screen, event, cx, cy+i+1, cellWidth-1, tview.AlignLeft, color)
+			}
+
+			day = day.AddDate(0, 0, 1)
+		}
+	}
+}
+
+// drawHelp 在最后一行显示按键提示
+func (c *Calendar) drawHelp(screen tcell.Screen, x, y, width, height int, help string) {
+	if height < 4 {
+		return
+	}
+	tview.Print(screen, help, x, y+height-1, width, tview.AlignCenter, c.theme.Secondary)
+}
+
+// cellHeight 月视图每个格子的高度, 去掉标题/星期/提示三行
+func (c *Calendar) cellHeight(height int) int {
+	cellHeight := (height - 3) / 6
+	if cellHeight < 1 {
+		cellHeight = 1
+	}
+	return cellHeight
+}
+
+// drawWeek 周日程: 每天一个标题, 下面是事件
+func (c *Calendar) drawWeek(screen tcell.Screen, x, y, width, height int) {
+	today := dateOf(c.now())
+	start := weekStart(c.selected)
+	_, week := start.ISOWeek()
+	title := "[::b]" + i18n.T("calendar.week_title", week, start.Year())
+	tview.Print(screen, title, x, y, width, tview.AlignCenter, c.theme.Text)
+	c.drawHelp(screen, x, y, width, height, i18n.T("calendar.week_help"))
+
+	line := y + 1
+	for i := 0; i < 7 && line < y+height-1; i++ {
+		day := start.AddDate(0, 0, i)
+		fg, bg := c.theme.Accent, c.theme.Background
+		if day.Equal(c.selected) {
+			fg, bg = bg, fg
+		}
+		header := i18n.ShortWeekday(day.Weekday()) + " " + day.Format("01-02")
+		if day.Equal(today) {
+			header += "  (" + i18n.T("calendar.today") + ")"
+		}
+		printFilled(screen, "[::b]"+tview.Escape(header), x, line, width, fg, bg)
+		line++
+
+		for _, event := range c.eventsOf(day) {
+			if line >= y+height-1 {
+				return
+			}
+			tview.Print(screen, "  "+event, x, line, width, tview.AlignLeft, c.theme.Text)
+			line++
+		}
+	}
+}
+
+// InputHandler 键盘导航
+func (c *Calendar) InputHandler() func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+	return c.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+		day := c.selected
+		switch event.Key() {
+		case tcell.KeyLeft:
+			day = day.AddDate(0, 0, -1)
+		case tcell.KeyRight:
+			day = day.AddDate(0, 0, 1)
+		case tcell.KeyUp:
+			day = day.AddDate(0, 0, -7)
+		case tcell.KeyDown:
+			day = day.AddDate(0, 0, 7)
+		case tcell.KeyPgUp:
+			day = c.page(day, -1)
+		case tcell.KeyPgDn:
+			day = c.page(day, 1)
+		case tcell.KeyEnter:
+			if c.selectFn != nil {
+				c.selectFn(day)
+			}
+		case tcell.KeyRune:
+			switch event.Rune() {
+			case 'h':
+				day = day.AddDate(0, 0, -1)
+			case 'l':
+				day = day.AddDate(0, 0, 1)
+			case 'k':
+				day = day.AddDate(0, 0, -7)
+			case 'j':
+				day = day.AddDate(0, 0, 7)
+			case '<':
+				day = c.page(day, -1)
+			case '>':
+				day = c.page(day, 1)
+			case 't':
+				day = dateOf(c.now())
+			case 'm':
+				c.mode = CalendarMonth
+			case 'w':
+				c.mode = CalendarWeek
+			}
+		}
+		c.SetDate(day)
+	})
+}
+
+// page 翻页, 月视图翻一个月, 周日程翻一周
+func (c *Calendar) page(day time.Time, n int) time.Time {
+	if c.mode == CalendarWeek {
+		return day.AddDate(0, 0, 7*n)
+	}
+	// 避免1月31日加一个月变成3月
+	first := time.Date(day.Year(), day.Month()+time.Month(n), 1, 0, 0, 0, 0, day.Location())
+	last := first.AddDate(0, 1, -1)
+	if day.Day() > last.Day() {
+		return last
+	}
+	return first.AddDate(0, 0, day.Day()-1)
+}
+
+// MouseHandler 点击选中, 双击打开
+func (c *Calendar) MouseHandler() func(action tview.MouseAction, event *tcell.EventMouse, setFocus func(p tview.Primitive)) (consumed bool, capture tview.Primitive) {
+	return c.WrapMouseHandler(func(action tview.MouseAction, event *tcell.EventMouse, setFocus func(p tview.Primitive)) (consumed bool, capture tview.Primitive) {
+		if !c.InRect(event.Position()) {
+			return false, nil
+		}
+		if action != tview.MouseLeftClick && action != tview.MouseLeftDoubleClick {
+			return false, nil
+		}
+		setFocus(c)
+
+		day, ok := c.dayAt(event.Position())
+		if !ok {
+			return true, nil
+		}
+		c.SetDate(day)
+		if action == tview.MouseLeftDoubleClick && c.selectFn != nil {
+			c.selectFn(day)
+		}
+		return true, nil
+	})
+}
+
+// dayAt 坐标对应的日期, 仅月视图支持
+func (c *Calendar) dayAt(px, py int) (time.Time, bool) {
+	if c.mode != CalendarMonth {
+		return time.Time{}, false
+	}
+	x, y, width, height := c.GetInnerRect()
+	cellWidth := width / 7
+	cellHeight := c.cellHeight(height)
+	if cellWidth < 3 || py < y+2 || px >= x+cellWidth*7 {
+		return time.Time{}, false
+	}
+
+	week, wd := (py-y-2)/cellHeight, (px-x)/cellWidth
+	if week >= 6 {
+		return time.Time{}, false
+	}
+	first := time.Date(c.selected.Year(), c.selected.Month(), 1, 0, 0, 0, 0, c.selected.Location())
+	return weekStart(first).AddDate(0, 0, week*7+wd), true
+}
+
+// printFilled 先用背景色填充一行再打印文本
+func printFilled(screen tcell.Screen, text string, x, y, width int, fg, bg tcell.Color) {
+	style := tcell.StyleDefault.Background(bg)
+	for i := 0; i < width; i++ {
+		screen.SetContent(x+i, y, ' ', nil, style)
+	}
+	tview.Print(screen, text, x, y, width, tview.AlignLeft, fg)
+}
+
+// padRight 在右侧补空格到width列, 按显示宽度计算
+func padRight(text string, width int) string {
+	if w := tview.TaggedStringWidth(text); w < width {
+		return text + strings.Repeat(" ", width-w)
+	}
+	return text
+}
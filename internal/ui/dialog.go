@@ -0,0 +1,162 @@
+package ui
+
+import (
+	"fmt"
+	"kongtools/internal/i18n"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// 对话框的大小
+const (
+	dialogWidth         = 60
+	dialogMaxTextHeight = 12
+)
+
+// FormField 表单对话框的字段
+type FormField struct {
+	Label string
+	Value string
+}
+
+// dialog 对话框: 说明文本, 表单和错误提示
+type dialog struct {
+	*tview.Flex
+
+	text   *tview.TextView
+	form   *tview.Form
+	status *tview.TextView
+
+	name string
+	app  *App
+}
+
+func (a *App) newDialog(title, message string) *dialog {
+	a.dialogs++
+	d := dialog{
+		Flex:   tview.NewFlex(),
+		text:   tview.NewTextView(),
+		form:   tview.NewForm(),
+		status: tview.NewTextView(),
+		name:   fmt.Sprintf("dialog-%d", a.dialogs),
+		app:    a,
+	}
+
+	theme := a.Themes.Current()
+	theme.ApplyBox(d.Box)
+	theme.ApplyTextView(d.text)
+	theme.ApplyForm(d.form)
+	theme.ApplyTextView(d.status)
+	d.status.SetTextColor(theme.Error)
+
+	d.text.SetText(message).SetWordWrap(true)
+	d.form.SetButtonsAlign(tview.AlignCenter).
+		SetBorderPadding(0, 0, 0, 0)
+	d.SetDirection(tview.FlexRow).
+		SetBorder(true).
+		SetTitle(" "+title+" ").
+		SetBorderPadding(0, 0, 1, 1)
+
+	return &d
+}
+
+// show 按内容计算高度并显示, 点击外部或按Esc时调用cancel
+func (d *dialog) show(cancel func()) {
+	textHeight := 0
+	if message := d.text.GetText(false); message != "" {
+		for _, line := range strings.Split(message, "\n") {
+			textHeight += maxInt(1, len(tview.WordWrap(line, dialogWidth-4)))
+		}
+		if textHeight > dialogMaxTextHeight {
+			textHeight = dialogMaxTextHeight
+		}
+		d.AddItem(d.text, textHeight, 0, false)
+	}
+	// 每个字段后有一行间隔, 最后是按钮
+	formHeight := d.form.GetFormItemCount()*2 + 1
+	d.AddItem(d.form, formHeight, 0, true).
+		AddItem(d.status, 1, 0, false)
+
+	d.form.SetCancelFunc(cancel)
+	d.app.ShowOverlay(d.name, d, dialogWidth, textHeight+formHeight+3, cancel)
+}
+
+// close 关闭并恢复焦点
+func (d *dialog) close() {
+	d.app.HideOverlay(d.name)
+}
+
+// Confirm 显示确认对话框, 确认后调用ok
+func (a *App) Confirm(title, message, okLabel string, ok func()) {
+	d := a.newDialog(title, message)
+	d.form.AddButton(okLabel, func() {
+		d.close()
+		ok()
+	}).AddButton(i18n.T("dialog.cancel"), d.close)
+	d.show(d.close)
+}
+
+// Prompt 显示单行输入对话框, done返回错误时显示错误并保持打开
+func (a *App) Prompt(title, label, value string, done func(text string) error) {
+	a.FormDialog(title, "", []FormField{{Label: label, Value: value}}, func(values []string) error {
+		return done(values[0])
+	})
+}
+
+// FormDialog 显示多字段表单对话框, 字段的值按顺序传给submit.
+// submit返回错误时显示错误并保持打开.
+func (a *App) FormDialog(title, message string, fields []FormField, submit func(values []string) error) {
+	d := a.newDialog(title, message)
+	for _, field := range fields {
+		d.form.AddInputField(field.Label, field.Value, 0, nil, nil)
+	}
+	ok := func() {
+		values := make([]string, len(fields))
+		for i := range fields {
+			values[i] = d.form.GetFormItem(i).(*tview.InputField).GetText()
+		}
+		if err := submit(values); err != nil {
+			d.status.SetText(err.Error())
+			return
+		}
+		d.close()
+	}
+	// 在输入框中按Enter即提交
+	d.form.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if index, _ := d.form.GetFocusedItemIndex(); index >= 0 && event.Key() == tcell.KeyEnter {
+			ok()
+			return nil
+		}
+		return event
+	})
+	d.form.AddButton(i18n.T("dialog.ok"), ok).AddButton(i18n.T("dialog.cancel"), d.close)
+	d.show(d.close)
+}
+
+// ShowError 显示错误详情, 合并的多个错误逐行显示
+func (a *App) ShowError(title string, err error) {
+	var lines []string
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range joined.Unwrap() {
+			lines = append(lines, "• "+e.Error())
+		}
+	} else {
+		lines = append(lines, err.Error())
+	}
+
+	d := a.newDialog(title, strings.Join(lines, "\n"))
+	d.text.SetTextColor(a.Themes.Current().Error)
+	d.form.AddButton(i18n.T("dialog.close"), d.close)
+	// 上下键滚动错误详情
+	d.form.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyUp, tcell.KeyDown, tcell.KeyPgUp, tcell.KeyPgDn:
+			d.text.InputHandler()(event, nil)
+			return nil
+		}
+		return event
+	})
+	d.show(d.close)
+}
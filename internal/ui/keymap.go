@@ -0,0 +1,481 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"kongtools/internal/i18n"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/sagikazarmark/slog-shim"
+)
+
+// ScopeGlobal 全局作用域, 在所有页面生效
+const ScopeGlobal = "global"
+
+// chordTimeout 组合键两次按键的最长间隔
+const chordTimeout = time.Second
+
+// KeymapConfig 按键配置
+type KeymapConfig struct {
+	Preset   string                         // 预设: default 或 vim
+	Bindings map[string]map[string][]string // 作用域 -> 动作 -> 按键
+}
+
+// keyPresets 预设的按键, 覆盖动作的默认按键
+var keyPresets = map[string]map[string]map[string][]string{
+	"default": {},
+	"vim": {
+		"todo-list": {
+			"complete": {"x", "Space"},
+			"delete":   {"d d", "Delete"},
+			"edit":     {"i", "Enter"},
+			"due":      {"D"},
+			"timer":    {"s"},
+			"entries":  {"T"},
+		},
+		"board": {
+			"left":       {"h", "Left"},
+			"right":      {"l", "Right"},
+			"move-left":  {"H", "Shift+Left"},
+			"move-right": {"L", "Shift+Right"},
+		},
+	},
+}
+
+// Action 可绑定按键的动作
+type Action struct {
+	Scope       string
+	Name        string
+	Description string
+	Keys        []string // 规范化后的按键, 组合键用空格分隔, 如 "g g"
+	defaults    []string // 注册时的按键
+	handler     func()
+}
+
+// ID 动作的全名, 如 todo-list.complete
+func (a *Action) ID() string {
+	return a.Scope + "." + a.Name
+}
+
+// Keymap 按键映射和动作分发
+type Keymap struct {
+	actions []*Action
+	index   map[string]*Action
+
+	pending map[string]*chord // 各作用域组合键已按下的部分
+
+	logger *slog.Logger
+}
+
+// chord 组合键已按下的部分
+type chord struct {
+	keys []string
+	at   time.Time
+}
+
+// NewKeymap 新建
+func NewKeymap(logger *slog.Logger) *Keymap {
+	return &Keymap{
+		index:   map[string]*Action{},
+		pending: map[string]*chord{},
+		logger:  logger.With("module", "ui-keymap"),
+	}
+}
+
+// Register 注册动作及默认按键, 重复注册时更新描述和处理函数
+func (k *Keymap) Register(scope, name, description string, handler func(), keys ...string) {
+	id := scope + "." + name
+	a, ok := k.index[id]
+	if !ok {
+		a = &Action{Scope: scope, Name: name}
+		k.actions = append(k.actions, a)
+		k.index[id] = a
+		a.defaults = k.normalize(id, keys)
+		a.Keys = a.defaults
+	}
+	a.Description = description
+	a.handler = handler
+}
+
+// normalize 规范化按键, 无效的按键记日志后忽略
+func (k *Keymap) normalize(id string, keys []string) []string {
+	var result []string
+	for _, key := range keys {
+		seq, err := ParseKeySequence(key)
+		if err != nil {
+			k.logger.Warn(fmt.Sprintf("invalid key for %s: %s.", id, err))
+			continue
+		}
+		result = append(result, seq)
+	}
+	return result
+}
+
+// KeyPresets 所有预设的名称, 按名称排序
+func KeyPresets() []string {
+	names := make([]string, 0, len(keyPresets))
+	for name := range keyPresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Apply 应用预设和配置中的按键, 配置优先于预设
+func (k *Keymap) Apply(cfg KeymapConfig) error {
+	preset := cfg.Preset
+	if preset == "" {
+		preset = "default"
+	}
+	bindings, ok := keyPresets[preset]
+	if !ok {
+		return fmt.Errorf("unknown key preset %q", preset)
+	}
+
+	var errs []error
+	for _, layer := range []map[string]map[string][]string{bindings, cfg.Bindings} {
+		for scope, actions := range layer {
+			for name, keys := range actions {
+				if err := k.Bind(scope, name, keys...); err != nil {
+					errs = append(errs, err)
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Reload 恢复默认按键后重新应用按键配置并检查冲突, 有错误时保留原来的按键
+func (k *Keymap) Reload(cfg KeymapConfig) error {
+	saved := make([][]string, len(k.actions))
+	for i, a := range k.actions {
+		saved[i] = a.Keys
+		a.Keys = a.defaults
+	}
+	err := k.Apply(cfg)
+	if err == nil {
+		err = k.Check()
+	}
+	if err != nil {
+		for i, a := range k.actions {
+			a.Keys = saved[i]
+		}
+		return err
+	}
+	k.pending = map[string]*chord{}
+	return nil
+}
+
+// Unregister 移除作用域中的动作, name为空时移除整个作用域
+func (k *Keymap) Unregister(scope, name string) {
+	kept := k.actions[:0]
+	for _, a := range k.actions {
+		if a.Scope == scope && (name == "" || a.Name == name) {
+			delete(k.index, a.ID())
+			continue
+		}
+		kept = append(kept, a)
+	}
+	k.actions = kept
+	delete(k.pending, scope)
+}
+
+// Bind 替换动作的按键
+func (k *Keymap) Bind(scope, name string, keys ...string) error {
+	a, ok := k.index[scope+"."+name]
+	if !ok {
+		return fmt.Errorf("unknown action %s.%s", scope, name)
+	}
+
+	var seqs []string
+	for _, key := range keys {
+		seq, err := ParseKeySequence(key)
+		if err != nil {
+			return fmt.Errorf("%s: %w", a.ID(), err)
+		}
+		seqs = append(seqs, seq)
+	}
+	a.Keys = seqs
+	return nil
+}
+
+// Check 检查按键冲突: 同一作用域内或与全局作用域重复, 以及组合键的前缀被占用
+func (k *Keymap) Check() error {
+	var errs []error
+	for i, a := range k.actions {
+		for _, b := range k.actions[i+1:] {
+			if a.Scope != b.Scope && a.Scope != ScopeGlobal && b.Scope != ScopeGlobal {
+				continue
+			}
+			for _, ka := range a.Keys {
+				for _, kb := range b.Keys {
+					switch {
+					case ka == kb:
+						errs = append(errs, fmt.Errorf("key %q is bound to both %s and %s", ka, a.ID(), b.ID()))
+					case strings.HasPrefix(kb, ka+" "), strings.HasPrefix(ka, kb+" "):
+						errs = append(errs, fmt.Errorf("key %q of %s conflicts with %q of %s", ka, a.ID(), kb, b.ID()))
+					}
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Action 按作用域和名称查找动作
+func (k *Keymap) Action(scope, name string) (*Action, bool) {
+	a, ok := k.index[scope+"."+name]
+	return a, ok
+}
+
+// Actions 所有动作, 按注册顺序
+func (k *Keymap) Actions() []*Action {
+	return k.actions
+}
+
+// Run 执行动作
+func (k *Keymap) Run(scope, name string) bool {
+	a, ok := k.Action(scope, name)
+	if !ok || a.handler == nil {
+		return false
+	}
+	k.logger.Debug(fmt.Sprintf("run action: %s.", a.ID()))
+	a.handler()
+	return true
+}
+
+// Shortcut 动作的第一个单字符按键, 用于菜单等只支持字符快捷键的地方
+func (k *Keymap) Shortcut(scope, name string) rune {
+	a, ok := k.Action(scope, name)
+	if !ok {
+		return 0
+	}
+	for _, key := range a.Keys {
+		if r, size := utf8.DecodeRuneInString(key); size == len(key) {
+			return r
+		}
+	}
+	return 0
+}
+
+// Hint 动作按键的提示文本, 如 "Ctrl+P"
+func (k *Keymap) Hint(scope, name string) string {
+	a, ok := k.Action(scope, name)
+	if !ok {
+		return ""
+	}
+	return strings.Join(a.Keys, ", ")
+}
+
+// Dispatch 按作用域分发按键事件, 处理后返回nil, 否则返回原事件
+func (k *Keymap) Dispatch(scope string, event *tcell.EventKey) *tcell.EventKey {
+	key := EventKeyString(event)
+	pending := k.pending[scope]
+	if pending != nil && time.Since(pending.at) > chordTimeout {
+		pending = nil
+		delete(k.pending, scope)
+	}
+	var keys []string
+	if pending != nil {
+		keys = append(keys, pending.keys...)
+	}
+	seq := strings.Join(append(keys, key), " ")
+
+	prefix := false
+	for _, a := range k.actions {
+		if a.Scope != scope || a.handler == nil {
+			continue
+		}
+		for _, s := range a.Keys {
+			if s == seq {
+				delete(k.pending, scope)
+				k.logger.Debug(fmt.Sprintf("run action: %s, key: %s.", a.ID(), seq))
+				a.handler()
+				return nil
+			}
+			if strings.HasPrefix(s, seq+" ") {
+				prefix = true
+			}
+		}
+	}
+
+	if prefix {
+		k.pending[scope] = &chord{keys: append(keys, key), at: time.Now()}
+		return nil
+	}
+	if pending != nil {
+		// 组合键中断, 重新按单个按键处理
+		delete(k.pending, scope)
+		return k.Dispatch(scope, event)
+	}
+	return event
+}
+
+// HelpEntry 帮助列表的一项
+type HelpEntry struct {
+	Scope       string
+	Keys        string
+	Description string
+}
+
+// Help 按作用域排序的帮助列表, 全局作用域在前
+func (k *Keymap) Help() []HelpEntry {
+	entries := make([]HelpEntry, 0, len(k.actions))
+	for _, a := range k.actions {
+		keys := strings.Join(a.Keys, ", ")
+		if keys == "" {
+			keys = i18n.T("keys.unbound")
+		}
+		entries = append(entries, HelpEntry{Scope: a.Scope, Keys: keys, Description: a.Description})
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if (entries[i].Scope == ScopeGlobal) != (entries[j].Scope == ScopeGlobal) {
+			return entries[i].Scope == ScopeGlobal
+		}
+		return entries[i].Scope < entries[j].Scope
+	})
+	return entries
+}
+
+// keyNames 小写名称 -> 按键, 由tcell.KeyNames生成
+var keyNames = func() map[string]tcell.Key {
+	names := map[string]tcell.Key{}
+	for key, name := range tcell.KeyNames {
+		names[strings.ToLower(name)] = key
+	}
+	names["escape"] = tcell.KeyEsc
+	names["return"] = tcell.KeyEnter
+	names["del"] = tcell.KeyDelete
+	return names
+}()
+
+// ParseKeySequence 解析并规范化按键, 组合键用空格分隔, 如 "ctrl+p", "g g"
+func ParseKeySequence(s string) (string, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return "", fmt.Errorf("empty key")
+	}
+	keys := make([]string, 0, len(fields))
+	for _, f := range fields {
+		key, err := parseKey(f)
+		if err != nil {
+			return "", err
+		}
+		keys = append(keys, key)
+	}
+	return strings.Join(keys, " "), nil
+}
+
+// parseKey 解析单个按键, 如 Ctrl+P, Alt+Left, Space, x
+func parseKey(s string) (string, error) {
+	key, r, mods, err := parseKeyParts(s)
+	if err != nil {
+		return "", err
+	}
+	return keyString(key, r, mods), nil
+}
+
+// ParseKeyEvent 把单个按键解析为按键事件, 如 Ctrl+P, Alt+Left, Space, x
+func ParseKeyEvent(s string) (*tcell.EventKey, error) {
+	key, r, mods, err := parseKeyParts(s)
+	if err != nil {
+		return nil, err
+	}
+	return tcell.NewEventKey(key, r, mods), nil
+}
+
+func parseKeyParts(s string) (tcell.Key, rune, tcell.ModMask, error) {
+	var mods tcell.ModMask
+	rest := s
+	for {
+		i := strings.Index(rest, "+")
+		if i <= 0 || i == len(rest)-1 {
+			break
+		}
+		switch strings.ToLower(rest[:i]) {
+		case "ctrl":
+			mods |= tcell.ModCtrl
+		case "alt":
+			mods |= tcell.ModAlt
+		case "shift":
+			mods |= tcell.ModShift
+		default:
+			return 0, 0, 0, fmt.Errorf("invalid modifier in key %q", s)
+		}
+		rest = rest[i+1:]
+	}
+
+	if strings.EqualFold(rest, "space") {
+		rest = " "
+	}
+	if r, size := utf8.DecodeRuneInString(rest); size == len(rest) {
+		if u := unicode.ToUpper(r); mods&tcell.ModCtrl != 0 && u >= 'A' && u <= 'Z' {
+			// Ctrl+字母是独立的按键
+			return tcell.KeyCtrlA + tcell.Key(u-'A'), 0, mods, nil
+		}
+		return tcell.KeyRune, r, mods &^ tcell.ModShift, nil
+	}
+
+	key, ok := keyNames[strings.ToLower(rest)]
+	if !ok {
+		return 0, 0, 0, fmt.Errorf("unknown key %q", s)
+	}
+	return key, 0, mods, nil
+}
+
+// EventKeyString 按键事件的规范化文本
+func EventKeyString(event *tcell.EventKey) string {
+	mods := event.Modifiers()
+	if event.Key() == tcell.KeyRune {
+		// 字符已经包含了Shift
+		mods &^= tcell.ModShift
+	}
+	return keyString(event.Key(), event.Rune(), mods)
+}
+
+func keyString(key tcell.Key, r rune, mods tcell.ModMask) string {
+	var name string
+	switch {
+	case key == tcell.KeyRune && r == ' ':
+		name = "Space"
+	case key == tcell.KeyRune:
+		name = string(r)
+	default:
+		var ok bool
+		if name, ok = tcell.KeyNames[key]; !ok {
+			name = fmt.Sprintf("Key[%d]", key)
+		}
+		if strings.HasPrefix(name, "Ctrl-") {
+			name = name[len("Ctrl-"):]
+			mods |= tcell.ModCtrl
+		}
+	}
+
+	var b strings.Builder
+	if mods&tcell.ModCtrl != 0 {
+		b.WriteString("Ctrl+")
+	}
+	if mods&tcell.ModAlt != 0 {
+		b.WriteString("Alt+")
+	}
+	if mods&tcell.ModShift != 0 {
+		b.WriteString("Shift+")
+	}
+	b.WriteString(name)
+	return b.String()
+}
+
+// IsTextInput 焦点是否在文本输入控件上, 此时不处理无修饰键的全局按键
+func IsTextInput(p tview.Primitive) bool {
+	switch p.(type) {
+	case *tview.InputField, *tview.TextArea:
+		return true
+	}
+	return false
+}
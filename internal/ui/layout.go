@@ -0,0 +1,362 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"kongtools/internal/i18n"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/sagikazarmark/slog-shim"
+)
+
+// SplitMode 分屏方式
+type SplitMode int
+
+// 分屏方式
+const (
+	SplitNone       SplitMode = iota // 不分屏
+	SplitVertical                    // 左右并排
+	SplitHorizontal                  // 上下堆叠
+)
+
+// 侧边栏的宽度
+const (
+	sidebarMinWidth = 10 // 最小宽度, 内容区也至少保留这么宽
+	sidebarStep     = 2  // 每次按键调整的宽度
+)
+
+// Layout 布局: 可折叠, 可调整宽度的侧边栏和可分屏的内容区
+type Layout struct {
+	*tview.Box
+
+	sidebar   tview.Primitive
+	primary   tview.Primitive
+	secondary *Pages
+	name      string // 分屏中的页面, 为空表示没有
+
+	sidebarWidth int // 0表示总宽度的1/4
+	collapsed    bool
+	split        SplitMode
+	dragging     bool // 正在用鼠标拖动侧边栏边框
+
+	logger *slog.Logger
+}
+
+// NewLayout 新建
+func NewLayout(logger *slog.Logger, sidebar, primary tview.Primitive) *Layout {
+	return &Layout{
+		Box:       tview.NewBox(),
+		sidebar:   sidebar,
+		primary:   primary,
+		secondary: NewPages(logger),
+		logger:    logger.With("module", "ui-layout"),
+	}
+}
+
+// ApplyTheme 应用主题
+func (l *Layout) ApplyTheme(theme *Theme) {
+	theme.ApplyBox(l.Box)
+	l.secondary.ApplyTheme(theme)
+}
+
+// ToggleSidebar 折叠或展开侧边栏
+func (l *Layout) ToggleSidebar() {
+	l.collapsed = !l.collapsed
+}
+
+// SidebarVisible 侧边栏是否显示
+func (l *Layout) SidebarVisible() bool {
+	return !l.collapsed
+}
+
+// ResizeSidebar 调整侧边栏宽度, 折叠时展开
+func (l *Layout) ResizeSidebar(delta int) {
+	_, _, width, _ := l.GetInnerRect()
+	l.collapsed = false
+	l.sidebarWidth = l.clampSidebar(l.currentSidebarWidth(width)+delta, width)
+}
+
+// currentSidebarWidth 总宽度为width时侧边栏的宽度, 折叠时为0
+func (l *Layout) currentSidebarWidth(width int) int {
+	if l.collapsed {
+		return 0
+	}
+	sw := l.sidebarWidth
+	if sw == 0 {
+		sw = width / 4
+	}
+	return l.clampSidebar(sw, width)
+}
+
+func (l *Layout) clampSidebar(sw, width int) int {
+	if sw > width-sidebarMinWidth {
+		sw = width - sidebarMinWidth
+	}
+	if sw < sidebarMinWidth {
+		sw = sidebarMinWidth
+	}
+	return sw
+}
+
+// SetSplit 设置分屏方式
+func (l *Layout) SetSplit(mode SplitMode) {
+	l.split = mode
+}
+
+// Split 分屏方式, 没有分屏页面时为SplitNone
+func (l *Layout) Split() SplitMode {
+	if l.name == "" {
+		return SplitNone
+	}
+	return l.split
+}
+
+// SetSecondary 在分屏中显示页面
+func (l *Layout) SetSecondary(name string, p tview.Primitive) {
+	if l.name != "" {
+		l.secondary.RemovePage(l.name)
+	}
+	l.name = name
+	l.secondary.AddPage(name, p, true, true)
+	if l.split == SplitNone {
+		l.split = SplitVertical
+	}
+}
+
+// CloseSecondary 关闭分屏
+func (l *Layout) CloseSecondary() {
+	if l.name != "" {
+		l.secondary.RemovePage(l.name)
+	}
+	l.name = ""
+	l.split = SplitNone
+}
+
+// Secondary 分屏中的页面名称, 没有时为空
+func (l *Layout) Secondary() string {
+	return l.name
+}
+
+// SecondaryPane 分屏的容器
+func (l *Layout) SecondaryPane() tview.Primitive {
+	return l.secondary
+}
+
+// children 显示中的子控件
+func (l *Layout) children() []tview.Primitive {
+	var children []tview.Primitive
+	if !l.collapsed {
+		children = append(children, l.sidebar)
+	}
+	children = append(children, l.primary)
+	if l.Split() != SplitNone {
+		children = append(children, l.secondary)
+	}
+	return children
+}
+
+// Draw 绘制
+func (l *Layout) Draw(screen tcell.Screen) {
+	l.Box.DrawForSubclass(screen, l)
+	x, y, width, height := l.GetInnerRect()
+
+	sw := l.currentSidebarWidth(width)
+	if sw > 0 {
+		l.sidebar.SetRect(x, y, sw, height)
+	}
+	cx, cw := x+sw, width-sw
+	switch l.Split() {
+	case SplitVertical:
+		pw := cw / 2
+		l.primary.SetRect(cx, y, pw, height)
+		l.secondary.SetRect(cx+pw, y, cw-pw, height)
+	case SplitHorizontal:
+		ph := height / 2
+		l.primary.SetRect(cx, y, cw, ph)
+		l.secondary.SetRect(cx, y+ph, cw, height-ph)
+	default:
+		l.primary.SetRect(cx, y, cw, height)
+	}
+
+	for _, p := range l.children() {
+		p.Draw(screen)
+	}
+}
+
+// Focus 焦点交给侧边栏, 折叠时交给内容
+func (l *Layout) Focus(delegate func(p tview.Primitive)) {
+	if !l.collapsed {
+		delegate(l.sidebar)
+		return
+	}
+	delegate(l.primary)
+}
+
+// HasFocus 是否有子控件获得焦点
+func (l *Layout) HasFocus() bool {
+	for _, p := range l.children() {
+		if p.HasFocus() {
+			return true
+		}
+	}
+	return l.Box.HasFocus()
+}
+
+// InputHandler 按键交给获得焦点的子控件
+func (l *Layout) InputHandler() func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+	return l.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+		for _, p := range l.children() {
+			if p.HasFocus() {
+				if handler := p.InputHandler(); handler != nil {
+					handler(event, setFocus)
+				}
+				return
+			}
+		}
+	})
+}
+
+// MouseHandler 拖动侧边栏的右边框调整宽度, 其他事件交给子控件
+func (l *Layout) MouseHandler() func(action tview.MouseAction, event *tcell.EventMouse, setFocus func(p tview.Primitive)) (consumed bool, capture tview.Primitive) {
+	return l.WrapMouseHandler(func(action tview.MouseAction, event *tcell.EventMouse, setFocus func(p tview.Primitive)) (consumed bool, capture tview.Primitive) {
+		mx, my := event.Position()
+		x, _, width, _ := l.GetInnerRect()
+		if l.dragging {
+			switch action {
+			case tview.MouseMove:
+				l.sidebarWidth = l.clampSidebar(mx-x+1, width)
+			case tview.MouseLeftUp:
+				l.dragging = false
+				return true, nil
+			}
+			return true, l
+		}
+		if !l.InRect(mx, my) {
+			return false, nil
+		}
+
+		if sw := l.currentSidebarWidth(width); action == tview.MouseLeftDown && sw > 0 && mx == x+sw-1 {
+			l.dragging = true
+			return true, l
+		}
+		for _, p := range l.children() {
+			if consumed, capture = p.MouseHandler()(action, event, setFocus); consumed {
+				return
+			}
+		}
+		return
+	})
+}
+
+// layoutState 布局的会话状态
+type layoutState struct {
+	SidebarWidth int       `json:"sidebarWidth"` // 0表示默认宽度
+	Collapsed    bool      `json:"collapsed"`
+	Split        SplitMode `json:"split"`
+	Secondary    string    `json:"secondary,omitempty"`
+}
+
+// layoutHook 保存和恢复布局
+type layoutHook struct {
+	app *App
+}
+
+// SaveState 保存布局
+func (h layoutHook) SaveState() any {
+	l := h.app.Layout
+	return layoutState{
+		SidebarWidth: l.sidebarWidth,
+		Collapsed:    l.collapsed,
+		Split:        l.Split(),
+		Secondary:    l.name,
+	}
+}
+
+// RestoreState 恢复布局
+func (h layoutHook) RestoreState(data []byte) error {
+	var state layoutState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+	l := h.app.Layout
+	l.sidebarWidth = state.SidebarWidth
+	l.collapsed = state.Collapsed
+	if state.Secondary != "" && state.Split != SplitNone {
+		if p, ok := h.app.Registry.Page(state.Secondary); ok {
+			l.SetSecondary(state.Secondary, p.Primitive())
+			l.SetSplit(state.Split)
+		}
+	}
+	return nil
+}
+
+// ToggleSidebar 折叠或展开侧边栏, 折叠时焦点移到内容
+func (a *App) ToggleSidebar() {
+	a.Layout.ToggleSidebar()
+	if !a.Layout.SidebarVisible() && a.Menu().HasFocus() {
+		a.SetFocus(a.Content)
+	}
+}
+
+// OpenSplit 在分屏中打开页面, 不能与当前页面相同
+func (a *App) OpenSplit(name string) {
+	p, ok := a.Registry.Page(name)
+	if !ok {
+		a.logger.Warn(fmt.Sprintf("split unknown page: %s.", name))
+		return
+	}
+	if name == a.Navigator.Current().Name {
+		a.Notify(SeverityWarning, i18n.T("split.already_open", p.Info().Title))
+		return
+	}
+	a.Layout.SetSecondary(name, p.Primitive())
+	p.OnFocus()
+}
+
+// CycleSplit 依次切换为左右分屏, 上下分屏和不分屏.
+// 没有分屏页面时打开上一个页面.
+func (a *App) CycleSplit() {
+	switch a.Layout.Split() {
+	case SplitVertical:
+		a.Layout.SetSplit(SplitHorizontal)
+		return
+	case SplitHorizontal:
+		if a.Layout.SecondaryPane().HasFocus() {
+			a.SetFocus(a.Content)
+		}
+		a.Layout.CloseSecondary()
+		return
+	}
+
+	current := a.Navigator.Current().Name
+	name := ""
+	if back := a.Navigator.back; len(back) > 0 && back[len(back)-1].Name != current {
+		name = back[len(back)-1].Name
+	}
+	for _, p := range a.Registry.Pages() {
+		if name != "" {
+			break
+		}
+		if p.Info().Name != current {
+			name = p.Info().Name
+		}
+	}
+	if name == "" {
+		a.Notify(SeverityWarning, i18n.T("split.no_page"))
+		return
+	}
+	a.OpenSplit(name)
+}
+
+// FocusNextPane 焦点在主页面和分屏之间切换
+func (a *App) FocusNextPane() {
+	if a.Layout.Split() == SplitNone {
+		return
+	}
+	if a.Layout.SecondaryPane().HasFocus() {
+		a.SetFocus(a.Content)
+	} else {
+		a.SetFocus(a.Layout.SecondaryPane())
+	}
+}
@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+// 行内的Markdown格式, 按顺序处理
+var (
+	markdownCode   = regexp.MustCompile("`([^`]+)`")
+	markdownBold   = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	markdownItalic = regexp.MustCompile(`\*([^*\s][^*]*)\*|\b_([^_\s][^_]*)_\b`)
+	markdownList   = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(.*)$`)
+	markdownHead   = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+)
+
+// RenderMarkdown 把基本的Markdown转为tview的样式标签: 标题, 列表, 引用, 代码块, 行内代码, 粗体和斜体.
+// 其他文本会被转义, 原样显示.
+func RenderMarkdown(text string, theme *Theme) string {
+	var b strings.Builder
+	fence := false
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			fence = !fence
+			continue
+		}
+		if fence {
+			b.WriteString(Tag(theme.Accent) + "  " + tview.Escape(line) + "[-]\n")
+			continue
+		}
+
+		switch m := markdownHead.FindStringSubmatch(trimmed); {
+		case m != nil:
+			// 一级标题加下划线
+			style := "[::b]"
+			if len(m[1]) == 1 {
+				style = "[::bu]"
+			}
+			b.WriteString(Tag(theme.Title) + style + renderInline(m[2], theme) + "[::-][-]\n")
+		case strings.HasPrefix(trimmed, ">"):
+			quote := strings.TrimSpace(strings.TrimPrefix(trimmed, ">"))
+			b.WriteString(Tag(theme.Secondary) + "│ " + renderInline(quote, theme) + "[-]\n")
+		case markdownList.MatchString(line):
+			m := markdownList.FindStringSubmatch(line)
+			bullet := "•"
+			if !strings.ContainsAny(m[2], "-*+") {
+				bullet = m[2]
+			}
+			b.WriteString(m[1] + Tag(theme.Accent) + bullet + "[-] " + renderInline(m[3], theme) + "\n")
+		default:
+			b.WriteString(renderInline(line, theme) + "\n")
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// renderInline 行内代码, 粗体和斜体, 代码中的内容不再处理
+func renderInline(line string, theme *Theme) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range markdownCode.FindAllStringSubmatchIndex(line, -1) {
+		b.WriteString(renderEmphasis(line[last:loc[0]]))
+		b.WriteString(Tag(theme.Accent) + tview.Escape(line[loc[2]:loc[3]]) + "[-]")
+		last = loc[1]
+	}
+	b.WriteString(renderEmphasis(line[last:]))
+	return b.String()
+}
+
+// renderEmphasis 粗体和斜体
+func renderEmphasis(text string) string {
+	text = tview.Escape(text)
+	text = markdownBold.ReplaceAllStringFunc(text, func(s string) string {
+		return "[::b]" + s[2:len(s)-2] + "[::-]"
+	})
+	return markdownItalic.ReplaceAllStringFunc(text, func(s string) string {
+		return "[::i]" + s[1:len(s)-1] + "[::-]"
+	})
+}
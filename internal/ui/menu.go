@@ -2,6 +2,7 @@ package ui
 
 import (
 	"fmt"
+	"kongtools/internal/i18n"
 
 	"github.com/rivo/tview"
 	"github.com/sagikazarmark/slog-shim"
@@ -20,7 +21,7 @@ func NewMenu(logger *slog.Logger) *Menu {
 		List:   tview.NewList(),
 		logger: logger.With("module", "ui-menu"),
 	}
-	m.SetBorder(true).SetTitle("Menu")
+	m.SetBorder(true).SetTitle(i18n.T("menu.title"))
 
 	return m
 }
@@ -38,3 +39,8 @@ func (m *Menu) AddItem(text, secondaryText string, shortcut rune, selected func(
 	m.List.AddItem(text, secondaryText, shortcut, selected)
 	return m
 }
+
+// ApplyTheme 应用主题
+func (m *Menu) ApplyTheme(theme *Theme) {
+	theme.ApplyList(m.List)
+}
@@ -0,0 +1,159 @@
+package ui
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"strings"
+)
+
+// navigationLimit 后退历史最多保留的页面数
+const navigationLimit = 50
+
+// Route 页面及参数, 文本形式如 todo-list?due=2024-05-01
+type Route struct {
+	Name   string
+	Params map[string]string
+}
+
+// ParseRoute 解析文本形式的路由
+func ParseRoute(s string) (Route, error) {
+	name, query, _ := strings.Cut(strings.TrimSpace(s), "?")
+	route := Route{Name: name}
+	if query == "" {
+		return route, nil
+	}
+
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return route, fmt.Errorf("invalid page params %q: %w", query, err)
+	}
+	route.Params = map[string]string{}
+	for key := range values {
+		route.Params[key] = values.Get(key)
+	}
+	return route, nil
+}
+
+// String 文本形式
+func (r Route) String() string {
+	if len(r.Params) == 0 {
+		return r.Name
+	}
+	values := url.Values{}
+	for key, value := range r.Params {
+		values.Set(key, value)
+	}
+	return r.Name + "?" + values.Encode()
+}
+
+// equal 页面和参数是否相同
+func (r Route) equal(other Route) bool {
+	return r.Name == other.Name && maps.Equal(r.Params, other.Params)
+}
+
+// Routable 接受参数的页面, 切换到该页面时在OnFocus之前调用
+type Routable interface {
+	SetParams(params map[string]string)
+}
+
+// Navigator 页面的后退和前进历史
+type Navigator struct {
+	back    []Route
+	forward []Route
+	current Route
+}
+
+// Current 当前页面
+func (n *Navigator) Current() Route {
+	return n.current
+}
+
+// push 切换到新页面, 清空前进历史. 与当前页面相同时不记录
+func (n *Navigator) push(route Route) {
+	if route.equal(n.current) {
+		return
+	}
+	if n.current.Name != "" {
+		n.back = append(n.back, n.current)
+		if len(n.back) > navigationLimit {
+			n.back = n.back[len(n.back)-navigationLimit:]
+		}
+	}
+	n.forward = nil
+	n.current = route
+}
+
+// goBack 后退, 没有历史时返回false
+func (n *Navigator) goBack() bool {
+	if len(n.back) == 0 {
+		return false
+	}
+	n.forward = append(n.forward, n.current)
+	n.current = n.back[len(n.back)-1]
+	n.back = n.back[:len(n.back)-1]
+	return true
+}
+
+// goForward 前进, 没有历史时返回false
+func (n *Navigator) goForward() bool {
+	if len(n.forward) == 0 {
+		return false
+	}
+	n.back = append(n.back, n.current)
+	n.current = n.forward[len(n.forward)-1]
+	n.forward = n.forward[:len(n.forward)-1]
+	return true
+}
+
+// Navigate 切换到已注册的页面并记录历史, params传给实现了Routable的页面
+func (a *App) Navigate(name string, params map[string]string) {
+	if _, ok := a.Registry.Page(name); !ok {
+		a.logger.Warn(fmt.Sprintf("switch to unknown page: %s.", name))
+		return
+	}
+	route := Route{Name: name, Params: params}
+	a.Navigator.push(route)
+	a.show(route)
+}
+
+// Back 后退到上一个页面
+func (a *App) Back() {
+	if a.Navigator.goBack() {
+		a.show(a.Navigator.Current())
+	}
+}
+
+// Forward 前进到下一个页面
+func (a *App) Forward() {
+	if a.Navigator.goForward() {
+		a.show(a.Navigator.Current())
+	}
+}
+
+// show 显示页面
+func (a *App) show(route Route) {
+	p, ok := a.Registry.Page(route.Name)
+	if !ok {
+		return
+	}
+
+	a.logger.Debug(fmt.Sprintf("show page: %s.", route))
+	if route.Name == a.Layout.Secondary() {
+		// 页面已在分屏中, 与当前页面交换
+		previous, _ := a.Content.GetFrontPage()
+		if prev, ok := a.Registry.Page(previous); ok && previous != route.Name {
+			a.Layout.SetSecondary(previous, prev.Primitive())
+		} else {
+			a.Layout.CloseSecondary()
+		}
+	}
+	a.Content.SwitchToPage(route.Name)
+	if r, ok := p.(Routable); ok {
+		r.SetParams(route.Params)
+	}
+	p.OnFocus()
+	if a.changed != nil {
+		a.changed(route.Name)
+	}
+}
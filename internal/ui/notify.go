@@ -0,0 +1,273 @@
+package ui
+
+import (
+	"fmt"
+	"kongtools/internal/i18n"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/sagikazarmark/slog-shim"
+)
+
+// Severity 通知的级别
+type Severity int
+
+// 通知的级别
+const (
+	SeverityInfo Severity = iota
+	SeveritySuccess
+	SeverityWarning
+	SeverityError
+)
+
+// 通知的显示设置
+const (
+	toastTimeout = 3 * time.Second // 错误显示两倍的时间
+	toastLimit   = 3               // 最多同时显示的通知数
+	historyLimit = 200             // 最多保留的历史通知数
+)
+
+// String 级别名称
+func (s Severity) String() string {
+	switch s {
+	case SeveritySuccess:
+		return "success"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	}
+	return "info"
+}
+
+// color 级别对应的主题颜色
+func (s Severity) color(theme *Theme) tcell.Color {
+	switch s {
+	case SeveritySuccess:
+		return theme.Success
+	case SeverityWarning:
+		return theme.Accent
+	case SeverityError:
+		return theme.Error
+	}
+	return theme.Hint
+}
+
+// timeout 通知显示的时间
+func (s Severity) timeout() time.Duration {
+	if s == SeverityError {
+		return 2 * toastTimeout
+	}
+	return toastTimeout
+}
+
+// Notification 通知
+type Notification struct {
+	ID       int
+	Time     time.Time
+	Severity Severity
+	Message  string
+}
+
+// Notifier 通知队列和历史, 可以在任意goroutine中发送通知
+type Notifier struct {
+	mutex   sync.Mutex
+	nextID  int
+	toasts  []Notification // 显示中的通知, 最新的在后
+	history []Notification // 最新的在后
+
+	redraw func() // 通知变化后请求重绘
+
+	logger *slog.Logger
+}
+
+// NewNotifier 新建, redraw在通知变化后调用, 可能在任意goroutine中
+func NewNotifier(logger *slog.Logger, redraw func()) *Notifier {
+	return &Notifier{
+		redraw: redraw,
+		logger: logger.With("module", "ui-notifier"),
+	}
+}
+
+// Post 发送通知, 超时后自动消失
+func (n *Notifier) Post(severity Severity, message string) {
+	n.mutex.Lock()
+	n.nextID++
+	item := Notification{ID: n.nextID, Time: time.Now(), Severity: severity, Message: message}
+	n.history = append(n.history, item)
+	if len(n.history) > historyLimit {
+		n.history = n.history[len(n.history)-historyLimit:]
+	}
+	n.toasts = append(n.toasts, item)
+	if len(n.toasts) > toastLimit {
+		n.toasts = n.toasts[len(n.toasts)-toastLimit:]
+	}
+	n.mutex.Unlock()
+
+	n.logger.Debug(fmt.Sprintf("notify %s: %s.", severity, message))
+	time.AfterFunc(severity.timeout(), func() {
+		n.dismiss(item.ID)
+	})
+	n.redraw()
+}
+
+// dismiss 移除显示中的通知
+func (n *Notifier) dismiss(id int) {
+	n.mutex.Lock()
+	removed := false
+	for i, item := range n.toasts {
+		if item.ID == id {
+			n.toasts = append(n.toasts[:i], n.toasts[i+1:]...)
+			removed = true
+			break
+		}
+	}
+	n.mutex.Unlock()
+
+	if removed {
+		n.redraw()
+	}
+}
+
+// Toasts 显示中的通知, 最新的在后
+func (n *Notifier) Toasts() []Notification {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	return append([]Notification{}, n.toasts...)
+}
+
+// History 历史通知, 最新的在后
+func (n *Notifier) History() []Notification {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	return append([]Notification{}, n.history...)
+}
+
+// Latest 最新的通知
+func (n *Notifier) Latest() (Notification, bool) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if len(n.history) == 0 {
+		return Notification{}, false
+	}
+	return n.history[len(n.history)-1], true
+}
+
+// StatusBar 状态栏: 左侧为最新的通知, 右侧为按键提示
+type StatusBar struct {
+	*tview.Box
+
+	notifier *Notifier
+	hints    func() string
+	theme    *Theme
+}
+
+// NewStatusBar 新建, hints返回右侧的按键提示
+func NewStatusBar(notifier *Notifier, hints func() string) *StatusBar {
+	return &StatusBar{
+		Box:      tview.NewBox(),
+		notifier: notifier,
+		hints:    hints,
+		theme:    builtinThemes[0],
+	}
+}
+
+// ApplyTheme 应用主题
+func (s *StatusBar) ApplyTheme(theme *Theme) {
+	s.theme = theme
+	theme.ApplyBox(s.Box)
+}
+
+// Draw 绘制
+func (s *StatusBar) Draw(screen tcell.Screen) {
+	s.Box.DrawForSubclass(screen, s)
+	x, y, width, _ := s.GetInnerRect()
+
+	hints := s.hints()
+	hintsWidth := tview.TaggedStringWidth(hints)
+	tview.Print(screen, hints, x, y, width, tview.AlignRight, s.theme.Secondary)
+
+	if item, ok := s.notifier.Latest(); ok {
+		color := item.Severity.color(s.theme)
+		if time.Since(item.Time) > item.Severity.timeout() {
+			color = s.theme.Secondary
+		}
+		text := item.Time.Format(time.TimeOnly) + " " + tview.Escape(item.Message)
+		tview.Print(screen, text, x+1, y, width-hintsWidth-2, tview.AlignLeft, color)
+	}
+}
+
+// drawToasts 在状态栏上方的右下角叠放显示中的通知, 最新的在最下面
+func (a *App) drawToasts(screen tcell.Screen) {
+	toasts := a.Notifier.Toasts()
+	if len(toasts) == 0 {
+		return
+	}
+
+	theme := a.Themes.Current()
+	_, y, _, _ := a.statusBar.GetRect()
+	screenWidth, _ := screen.Size()
+	for i := len(toasts) - 1; i >= 0; i-- {
+		y--
+		if y < 0 {
+			break
+		}
+		item := toasts[i]
+		text := " " + tview.Escape(item.Message) + " "
+		width := tview.TaggedStringWidth(text)
+		if width > screenWidth-2 {
+			width = screenWidth - 2
+		}
+		printFilled(screen, text, screenWidth-width-1, y, width, theme.SelectionText, item.Severity.color(theme))
+	}
+}
+
+// Notify 发送通知, 可以在任意goroutine中调用
+func (a *App) Notify(severity Severity, message string) {
+	a.Notifier.Post(severity, message)
+}
+
+// statusHints 状态栏右侧的按键提示
+func (a *App) statusHints() string {
+	var hints []string
+	for _, action := range []struct{ name, label string }{
+		{"palette", i18n.T("status.commands")},
+		{"messages", i18n.T("status.messages")},
+	} {
+		if key := a.Keymap.Hint(ScopeGlobal, action.name); key != "" {
+			hints = append(hints, key+" "+action.label)
+		}
+	}
+	return strings.Join(hints, "  ") + " "
+}
+
+// ShowMessages 显示历史通知
+func (a *App) ShowMessages() {
+	theme := a.Themes.Current()
+	history := a.Notifier.History()
+
+	var b strings.Builder
+	if len(history) == 0 {
+		b.WriteString(i18n.T("messages.empty"))
+	}
+	for i := len(history) - 1; i >= 0; i-- {
+		item := history[i]
+		fmt.Fprintf(&b, "%s %s%s[-] %s\n", item.Time.Format(time.TimeOnly),
+			Tag(item.Severity.color(theme)), padRight(i18n.T("severity."+item.Severity.String()), 7), tview.Escape(item.Message))
+	}
+
+	view := tview.NewTextView().
+		SetDynamicColors(true).
+		SetText(b.String())
+	theme.ApplyTextView(view)
+	view.SetBorder(true).SetTitle(" " + i18n.T("messages.title") + " ")
+	view.SetDoneFunc(func(tcell.Key) {
+		a.HideOverlay("messages")
+	})
+	a.ShowOverlay("messages", view, 80, 20, func() {
+		a.HideOverlay("messages")
+	})
+}
@@ -0,0 +1,79 @@
+package ui
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// overlay 居中显示在其他页面之上的控件, 点击外部时取消
+type overlay struct {
+	*tview.Flex
+
+	content tview.Primitive
+	cancel  func()
+}
+
+func newOverlay(content tview.Primitive, width, height int, cancel func()) *overlay {
+	o := overlay{
+		Flex:    tview.NewFlex(),
+		content: content,
+		cancel:  cancel,
+	}
+	o.AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(content, height, 0, true).
+			AddItem(nil, 0, 1, false), width, 0, true).
+		AddItem(nil, 0, 1, false)
+	return &o
+}
+
+// MouseHandler 浮层之外的鼠标事件不再传给下面的页面
+func (o *overlay) MouseHandler() func(action tview.MouseAction, event *tcell.EventMouse, setFocus func(p tview.Primitive)) (consumed bool, capture tview.Primitive) {
+	return o.WrapMouseHandler(func(action tview.MouseAction, event *tcell.EventMouse, setFocus func(p tview.Primitive)) (consumed bool, capture tview.Primitive) {
+		x, y := event.Position()
+		if cx, cy, width, height := o.content.GetRect(); x >= cx && x < cx+width && y >= cy && y < cy+height {
+			_, capture = o.content.MouseHandler()(action, event, setFocus)
+			return true, capture
+		}
+		if action == tview.MouseLeftClick && o.cancel != nil {
+			o.cancel()
+		}
+		return true, nil
+	})
+}
+
+// overlayEntry 已显示的浮层和显示前的焦点
+type overlayEntry struct {
+	name  string
+	focus tview.Primitive
+}
+
+// ShowOverlay 在所有页面之上居中显示p, cancel在点击浮层外部时调用
+func (a *App) ShowOverlay(name string, p tview.Primitive, width, height int, cancel func()) {
+	a.HideOverlay(name)
+	a.overlays = append(a.overlays, overlayEntry{name: name, focus: a.GetFocus()})
+	a.Main.AddPage(name, newOverlay(p, width, height, cancel), true, true)
+	a.SetFocus(p)
+}
+
+// HideOverlay 关闭浮层并恢复显示前的焦点
+func (a *App) HideOverlay(name string) {
+	for i := len(a.overlays) - 1; i >= 0; i-- {
+		entry := a.overlays[i]
+		if entry.name != name {
+			continue
+		}
+		a.overlays = append(a.overlays[:i], a.overlays[i+1:]...)
+		a.Main.RemovePage(name)
+		if entry.focus != nil {
+			a.SetFocus(entry.focus)
+		}
+		return
+	}
+}
+
+// HasOverlay 是否有浮层显示, 此时全局按键不生效
+func (a *App) HasOverlay() bool {
+	return len(a.overlays) > 0
+}
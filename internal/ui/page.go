@@ -22,6 +22,11 @@ func NewPages(logger *slog.Logger) *Pages {
 	return &p
 }
 
+// ApplyTheme 应用主题
+func (p *Pages) ApplyTheme(theme *Theme) {
+	theme.ApplyBox(p.Box)
+}
+
 // // GetFrontPage 获取当前页面
 // func (p *Pages) GetFrontPage() (string, tview.Primitive) {
 // 	return p.Pages.GetFrontPage()
@@ -0,0 +1,277 @@
+package ui
+
+import (
+	"fmt"
+	"kongtools/internal/i18n"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/sagikazarmark/slog-shim"
+)
+
+// 命令面板的大小
+const (
+	paletteWidth  = 72
+	paletteHeight = 18
+)
+
+// paletteRecentLimit 最多记录的最近使用命令数
+const paletteRecentLimit = 5
+
+// Command 命令面板中的命令
+type Command struct {
+	ID    string // 唯一标识, 用于记录最近使用
+	Title string
+	Hint  string // 按键提示
+	Run   func()
+}
+
+// Palette 命令面板, 模糊搜索并执行命令
+type Palette struct {
+	*tview.Flex
+
+	input *tview.InputField
+	table *tview.Table
+
+	commands []Command
+	matches  []Command
+	recent   []string // 最近使用的命令ID, 最近的在前
+
+	selected func(cmd Command)
+	canceled func()
+	theme    *Theme
+
+	logger *slog.Logger
+}
+
+// NewPalette 新建, selected在选中命令时调用, canceled在取消时调用
+func NewPalette(logger *slog.Logger, selected func(cmd Command), canceled func()) *Palette {
+	p := Palette{
+		Flex:     tview.NewFlex(),
+		input:    tview.NewInputField(),
+		table:    tview.NewTable(),
+		selected: selected,
+		canceled: canceled,
+		theme:    builtinThemes[0],
+		logger:   logger.With("module", "ui-palette"),
+	}
+
+	p.input.SetLabel("> ").
+		SetPlaceholder(i18n.T("palette.placeholder")).
+		SetChangedFunc(func(string) { p.filter() }).
+		SetInputCapture(p.handleInput)
+	p.table.SetSelectable(true, false).
+		SetSelectedFunc(func(int, int) { p.run() }).
+		SetDoneFunc(func(tcell.Key) { p.canceled() })
+	p.table.SetMouseCapture(func(action tview.MouseAction, event *tcell.EventMouse) (tview.MouseAction, *tcell.EventMouse) {
+		if action == tview.MouseLeftDoubleClick {
+			p.run()
+			return action, nil
+		}
+		return action, event
+	})
+
+	p.SetDirection(tview.FlexRow).
+		AddItem(p.input, 1, 0, true).
+		AddItem(p.table, 0, 1, false)
+	p.SetBorder(true).SetTitle(i18n.T("palette.title"))
+
+	return &p
+}
+
+// ApplyTheme 应用主题
+func (p *Palette) ApplyTheme(theme *Theme) {
+	p.theme = theme
+	theme.ApplyBox(p.Box)
+	theme.ApplyInputField(p.input)
+	theme.ApplyBox(p.table.Box)
+	p.table.SetSelectedStyle(tcell.StyleDefault.Background(theme.Selection).Foreground(theme.SelectionText))
+	p.render()
+}
+
+// Reset 清空输入并设置命令
+func (p *Palette) Reset(commands []Command) {
+	p.commands = commands
+	p.input.SetText("")
+	p.filter()
+}
+
+// handleInput 输入框中用上下键选择, Enter执行, Esc取消
+func (p *Palette) handleInput(event *tcell.EventKey) *tcell.EventKey {
+	switch event.Key() {
+	case tcell.KeyUp, tcell.KeyDown, tcell.KeyPgUp, tcell.KeyPgDn:
+		p.table.InputHandler()(event, nil)
+		return nil
+	case tcell.KeyEnter:
+		p.run()
+		return nil
+	case tcell.KeyEsc:
+		p.canceled()
+		return nil
+	}
+	return event
+}
+
+// filter 按输入模糊匹配命令, 无输入时最近使用的命令在前
+func (p *Palette) filter() {
+	query := strings.TrimSpace(p.input.GetText())
+	p.matches = p.matches[:0]
+
+	if query == "" {
+		for _, id := range p.recent {
+			for _, cmd := range p.commands {
+				if cmd.ID == id {
+					p.matches = append(p.matches, cmd)
+				}
+			}
+		}
+		for _, cmd := range p.commands {
+			if !p.isRecent(cmd.ID) {
+				p.matches = append(p.matches, cmd)
+			}
+		}
+	} else {
+		scores := map[string]int{}
+		for _, cmd := range p.commands {
+			if score, ok := fuzzyScore(query, cmd.Title); ok {
+				scores[cmd.ID] = score
+				p.matches = append(p.matches, cmd)
+			}
+		}
+		sort.SliceStable(p.matches, func(i, j int) bool {
+			return scores[p.matches[i].ID] > scores[p.matches[j].ID]
+		})
+	}
+
+	p.render()
+}
+
+func (p *Palette) render() {
+	p.table.Clear()
+	for row, cmd := range p.matches {
+		title := tview.Escape(cmd.Title)
+		if p.isRecent(cmd.ID) {
+			title += " " + Tag(p.theme.Secondary) + "(" + i18n.T("palette.recent") + ")[-]"
+		}
+		p.table.SetCell(row, 0, tview.NewTableCell(title).
+			SetTextColor(p.theme.Text).
+			SetExpansion(1))
+		p.table.SetCell(row, 1, tview.NewTableCell(tview.Escape(cmd.Hint)).
+			SetTextColor(p.theme.Accent).
+			SetAlign(tview.AlignRight))
+	}
+	p.table.Select(0, 0).ScrollToBeginning()
+}
+
+// run 执行选中的命令并记录为最近使用
+func (p *Palette) run() {
+	row, _ := p.table.GetSelection()
+	if row < 0 || row >= len(p.matches) {
+		return
+	}
+	cmd := p.matches[row]
+	p.logger.Debug(fmt.Sprintf("run command: %s.", cmd.ID))
+
+	recent := []string{cmd.ID}
+	for _, id := range p.recent {
+		if id != cmd.ID && len(recent) < paletteRecentLimit {
+			recent = append(recent, id)
+		}
+	}
+	p.recent = recent
+
+	p.selected(cmd)
+}
+
+func (p *Palette) isRecent(id string) bool {
+	for _, r := range p.recent {
+		if r == id {
+			return true
+		}
+	}
+	return false
+}
+
+// fuzzyScore 模糊匹配: pattern的字符按顺序出现在text中即匹配.
+// 连续匹配和单词开头的匹配得分更高.
+func fuzzyScore(pattern, text string) (int, bool) {
+	pr := []rune(strings.ToLower(pattern))
+	tr := []rune(strings.ToLower(text))
+
+	score, j, last := 0, 0, -2
+	for i, r := range tr {
+		if j == len(pr) {
+			break
+		}
+		if unicode.IsSpace(pr[j]) {
+			j++
+			continue
+		}
+		if r != pr[j] {
+			continue
+		}
+		score++
+		if i == last+1 {
+			score += 5
+		}
+		if i == 0 || !unicode.IsLetter(tr[i-1]) && !unicode.IsDigit(tr[i-1]) {
+			score += 3
+		}
+		last = i
+		j++
+	}
+	for j < len(pr) && unicode.IsSpace(pr[j]) {
+		j++
+	}
+	if j < len(pr) {
+		return 0, false
+	}
+	// 较短的文本更接近
+	return score*100 - len(tr), true
+}
+
+// commands 命令面板中的命令: 所有已注册的动作, 页面的动作会先切换到该页面
+func (a *App) commands() []Command {
+	var commands []Command
+	for _, action := range a.Keymap.Actions() {
+		if action.Scope == ScopeGlobal && action.Name == "palette" {
+			continue
+		}
+		scope, name := action.Scope, action.Name
+		cmd := Command{
+			ID:    action.ID(),
+			Title: action.Description,
+			Hint:  a.Keymap.Hint(scope, name),
+			Run:   func() { a.Keymap.Run(scope, name) },
+		}
+		if p, ok := a.Registry.Page(scope); ok {
+			cmd.Title = p.Info().Title + ": " + action.Description
+			cmd.Run = func() {
+				a.SwitchTo(scope)
+				a.SetFocus(a.Content)
+				a.Keymap.Run(scope, name)
+			}
+		}
+		commands = append(commands, cmd)
+	}
+	return commands
+}
+
+// ShowPalette 显示命令面板
+func (a *App) ShowPalette() {
+	a.palette.Reset(a.commands())
+	a.ShowOverlay("palette", a.palette, paletteWidth, paletteHeight, a.hidePalette)
+}
+
+func (a *App) hidePalette() {
+	a.HideOverlay("palette")
+}
+
+// runCommand 关闭面板后执行命令, 命令可以改变焦点
+func (a *App) runCommand(cmd Command) {
+	a.hidePalette()
+	cmd.Run()
+}
@@ -0,0 +1,93 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/rivo/tview"
+	"github.com/sagikazarmark/slog-shim"
+)
+
+// PageInfo 页面的基本信息
+type PageInfo struct {
+	Name        string // 唯一名称, 同时作为Content中的页面名
+	Title       string // 菜单中显示的名称
+	Description string // 菜单中的说明
+	Shortcut    rune   // 菜单快捷键
+}
+
+// Page 功能页面(工具)
+type Page interface {
+	// Info 页面信息
+	Info() PageInfo
+	// Primitive 页面内容
+	Primitive() tview.Primitive
+	// Init 在加入界面前调用一次
+	Init() error
+	// OnFocus 切换到该页面时调用
+	OnFocus()
+	// Shutdown 应用退出时调用
+	Shutdown()
+}
+
+// Registry 页面注册表, 按注册顺序排列
+type Registry struct {
+	pages []Page
+	index map[string]Page
+
+	logger *slog.Logger
+}
+
+// NewRegistry 新建
+func NewRegistry(logger *slog.Logger) *Registry {
+	return &Registry{
+		index:  map[string]Page{},
+		logger: logger.With("module", "ui-registry"),
+	}
+}
+
+// Register 注册页面, 名称不能重复
+func (r *Registry) Register(p Page) error {
+	name := p.Info().Name
+	if _, ok := r.index[name]; ok {
+		return fmt.Errorf("page %q already registered", name)
+	}
+	r.logger.Debug(fmt.Sprintf("register page: %s.", name))
+	r.pages = append(r.pages, p)
+	r.index[name] = p
+	return nil
+}
+
+// Unregister 移除页面
+func (r *Registry) Unregister(name string) {
+	if _, ok := r.index[name]; !ok {
+		return
+	}
+	r.logger.Debug(fmt.Sprintf("unregister page: %s.", name))
+	delete(r.index, name)
+	r.pages = slices.DeleteFunc(r.pages, func(p Page) bool {
+		return p.Info().Name == name
+	})
+}
+
+// sort 按pages的顺序排列已注册的页面
+func (r *Registry) sort(pages []Page) {
+	sorted := make([]Page, 0, len(r.pages))
+	for _, p := range pages {
+		if registered, ok := r.index[p.Info().Name]; ok {
+			sorted = append(sorted, registered)
+		}
+	}
+	r.pages = sorted
+}
+
+// Page 按名称查找页面
+func (r *Registry) Page(name string) (Page, bool) {
+	p, ok := r.index[name]
+	return p, ok
+}
+
+// Pages 所有页面
+func (r *Registry) Pages() []Page {
+	return r.pages
+}
@@ -0,0 +1,148 @@
+package ui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"kongtools/internal/pkg/xdg"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/sagikazarmark/slog-shim"
+)
+
+// Stateful 可以保存和恢复自身状态的页面或组件
+type Stateful interface {
+	// SaveState 退出时调用, 返回的状态会保存为JSON
+	SaveState() any
+	// RestoreState 从上次保存的JSON恢复
+	RestoreState(data []byte) error
+}
+
+// sessionFile 状态文件的内容
+type sessionFile struct {
+	Route  string                     `json:"route"`            // 上次的页面
+	States map[string]json.RawMessage `json:"states,omitempty"` // 各组件的状态
+}
+
+// Session 会话状态, 退出时保存, 下次启动时恢复
+type Session struct {
+	path  string
+	saved sessionFile
+	hooks map[string]Stateful
+	names []string // 按注册顺序
+
+	logger *slog.Logger
+}
+
+// DefaultStatePath 默认的状态文件, 在XDG状态目录下
+func DefaultStatePath() (string, error) {
+	dir, err := xdg.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "state.json"), nil
+}
+
+// NewSession 新建
+func NewSession(logger *slog.Logger, path string) *Session {
+	return &Session{
+		path:   path,
+		hooks:  map[string]Stateful{},
+		logger: logger.With("module", "ui-session"),
+	}
+}
+
+// Path 状态文件
+func (s *Session) Path() string {
+	return s.path
+}
+
+// Load 读取状态文件, 文件不存在时为空
+func (s *Session) Load() error {
+	if s.path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &s.saved); err != nil {
+		return fmt.Errorf("parse state file %s: %w", s.path, err)
+	}
+	return nil
+}
+
+// Route 上次的页面, 没有时为空
+func (s *Session) Route() string {
+	return s.saved.Route
+}
+
+// Register 注册组件, 已读取的状态在调用Restore时恢复
+func (s *Session) Register(name string, hook Stateful) {
+	if _, ok := s.hooks[name]; !ok {
+		s.names = append(s.names, name)
+	}
+	s.hooks[name] = hook
+}
+
+// Restore 恢复已注册组件的状态, 每份读取的状态只恢复一次
+func (s *Session) Restore(name string) {
+	hook, ok := s.hooks[name]
+	if !ok {
+		return
+	}
+	data, ok := s.saved.States[name]
+	if !ok {
+		return
+	}
+	delete(s.saved.States, name)
+	if err := hook.RestoreState(data); err != nil {
+		s.logger.Warn(fmt.Sprintf("restore state of %s: %s.", name, err))
+	}
+}
+
+// Unregister 移除组件, 之后不再保存它的状态
+func (s *Session) Unregister(name string) {
+	delete(s.hooks, name)
+	s.names = slices.DeleteFunc(s.names, func(n string) bool { return n == name })
+}
+
+// Save 收集各组件的状态并写入文件, 没有设置状态文件时不保存
+func (s *Session) Save(route string) error {
+	if s.path == "" {
+		return nil
+	}
+	file := sessionFile{Route: route, States: map[string]json.RawMessage{}}
+	for _, name := range s.names {
+		data, err := json.Marshal(s.hooks[name].SaveState())
+		if err != nil {
+			return fmt.Errorf("save state of %s: %w", name, err)
+		}
+		file.States[name] = data
+	}
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, data, 0o644)
+}
+
+// InitSession 读取会话状态, InitPages时实现了Stateful的页面会自动恢复
+func (a *App) InitSession(path string) error {
+	a.Session.path = path
+	return a.Session.Load()
+}
+
+// SaveSession 保存当前页面和各组件的状态
+func (a *App) SaveSession() error {
+	return a.Session.Save(a.Navigator.Current().String())
+}
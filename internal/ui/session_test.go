@@ -0,0 +1,96 @@
+package ui
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rivo/tview"
+)
+
+// listPage 有选中项的页面, 设置参数时像过滤一样重置选中项
+type listPage struct {
+	*tview.List
+	name string
+}
+
+func newListPage(name string) *listPage {
+	p := &listPage{List: tview.NewList(), name: name}
+	for _, item := range []string{"a", "b", "c"} {
+		p.AddItem(item, "", 0, nil)
+	}
+	return p
+}
+
+func (p *listPage) Info() PageInfo {
+	return PageInfo{Name: p.name, Title: p.name, Shortcut: rune(p.name[0])}
+}
+
+func (p *listPage) Primitive() tview.Primitive { return p }
+func (p *listPage) Init() error                { return nil }
+func (p *listPage) OnFocus()                   {}
+func (p *listPage) Shutdown()                  {}
+
+func (p *listPage) SetParams(map[string]string) { p.SetCurrentItem(0) }
+
+func (p *listPage) SaveState() any { return p.GetCurrentItem() }
+
+func (p *listPage) RestoreState(data []byte) error {
+	var current int
+	if err := json.Unmarshal(data, &current); err != nil {
+		return err
+	}
+	p.SetCurrentItem(current)
+	return nil
+}
+
+func TestSessionRestoresAfterParams(t *testing.T) {
+	tests := []struct {
+		name  string
+		home  string
+		route string
+		want  int
+	}{
+		{"saved route", "", "list?due=2024-05-01", 2},
+		{"other params win", "list?due=2024-06-01", "list?due=2024-06-01", 0},
+		{"no params", "list", "list", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "state.json")
+			data := `{"route": "list?due=2024-05-01", "states": {"list": 2, "other": 1}}`
+			if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			a := NewApp(testLogger())
+			if err := a.InitSession(path); err != nil {
+				t.Fatal(err)
+			}
+			list, other := newListPage("list"), newListPage("other")
+			for _, p := range []Page{list, other} {
+				if err := a.Registry.Register(p); err != nil {
+					t.Fatal(err)
+				}
+			}
+			home := tt.home
+			if home == "" {
+				home = a.Session.Route()
+			}
+			if err := a.InitPages(home, KeymapConfig{}); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := a.Navigator.Current().String(); got != tt.route {
+				t.Fatalf("route = %s, want %s", got, tt.route)
+			}
+			if got := list.GetCurrentItem(); got != tt.want {
+				t.Errorf("current page selection = %d, want %d", got, tt.want)
+			}
+			if got := other.GetCurrentItem(); got != 1 {
+				t.Errorf("other page selection = %d, want 1", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package ui
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/sagikazarmark/slog-shim"
+)
+
+// sparkRunes 从低到高的柱子
+var sparkRunes = []rune{'▁', '▂', '▃', '▄', '▅', '▆', '▇', '█'}
+
+// Sparkline 迷你折线图, 每个数据占一列, 数据多于宽度时只显示最后的部分
+type Sparkline struct {
+	*tview.Box
+
+	data  []float64
+	color tcell.Color
+	theme *Theme
+
+	logger *slog.Logger
+}
+
+// NewSparkline 新建
+func NewSparkline(logger *slog.Logger) *Sparkline {
+	return &Sparkline{
+		Box:    tview.NewBox(),
+		color:  builtinThemes[0].Accent,
+		theme:  builtinThemes[0],
+		logger: logger.With("module", "ui-sparkline"),
+	}
+}
+
+// SetData 设置数据
+func (s *Sparkline) SetData(data []float64) *Sparkline {
+	s.data = data
+	return s
+}
+
+// SetColor 设置颜色
+func (s *Sparkline) SetColor(color tcell.Color) *Sparkline {
+	s.color = color
+	return s
+}
+
+// ApplyTheme 应用主题, 颜色需要另外设置
+func (s *Sparkline) ApplyTheme(theme *Theme) {
+	s.theme = theme
+	theme.ApplyBox(s.Box)
+}
+
+// Draw 绘制, 高度大于1时用多行绘制
+func (s *Sparkline) Draw(screen tcell.Screen) {
+	s.Box.DrawForSubclass(screen, s)
+	x, y, width, height := s.GetInnerRect()
+	if width <= 0 || height <= 0 || len(s.data) == 0 {
+		return
+	}
+
+	data := s.data
+	if len(data) > width {
+		data = data[len(data)-width:]
+	}
+	max := 0.0
+	for _, v := range data {
+		if v > max {
+			max = v
+		}
+	}
+	if max == 0 {
+		max = 1
+	}
+
+	style := tcell.StyleDefault.Foreground(s.color).Background(s.GetBackgroundColor())
+	levels := len(sparkRunes)
+	for i, v := range data {
+		// 每行8级, 总共height*8级
+		n := int(v / max * float64(height*levels))
+		if v > 0 && n == 0 {
+			n = 1
+		}
+		for row := 0; row < height && n > 0; row++ {
+			r := sparkRunes[levels-1]
+			if n < levels {
+				r = sparkRunes[n-1]
+			}
+			screen.SetContent(x+i, y+height-1-row, r, nil, style)
+			n -= levels
+		}
+	}
+}
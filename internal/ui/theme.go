@@ -0,0 +1,337 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/sagikazarmark/slog-shim"
+	"gopkg.in/yaml.v3"
+)
+
+// ThemeConfig 主题配置
+type ThemeConfig struct {
+	Name string // 使用的主题
+	Dir  string // 用户主题目录, 其中的 *.yaml 文件
+}
+
+// Theme 主题
+type Theme struct {
+	Name string
+
+	Background    tcell.Color
+	Text          tcell.Color
+	Secondary     tcell.Color // 次要文本, 如标签, 说明
+	Border        tcell.Color
+	Title         tcell.Color
+	Selection     tcell.Color // 选中项和输入框的背景
+	SelectionText tcell.Color
+	Completed     tcell.Color
+	Hint          tcell.Color
+	Error         tcell.Color
+	Accent        tcell.Color // 强调, 如标签, 到期日, 计时
+	Success       tcell.Color
+}
+
+// 内置主题
+var builtinThemes = []*Theme{
+	{
+		Name:          "dark",
+		Background:    tcell.ColorBlack,
+		Text:          tcell.ColorWhite,
+		Secondary:     tcell.ColorGray,
+		Border:        tcell.ColorWhite,
+		Title:         tcell.ColorWhite,
+		Selection:     tcell.ColorWhite,
+		SelectionText: tcell.ColorBlack,
+		Completed:     tcell.ColorGray,
+		Hint:          tcell.ColorSteelBlue,
+		Error:         tcell.ColorRed,
+		Accent:        tcell.ColorYellow,
+		Success:       tcell.ColorGreen,
+	},
+	{
+		Name:          "light",
+		Background:    tcell.NewHexColor(0xffffff),
+		Text:          tcell.NewHexColor(0x1c1c1c),
+		Secondary:     tcell.NewHexColor(0x6c6c6c),
+		Border:        tcell.NewHexColor(0x444444),
+		Title:         tcell.NewHexColor(0x005f87),
+		Selection:     tcell.NewHexColor(0x005f87),
+		SelectionText: tcell.NewHexColor(0xffffff),
+		Completed:     tcell.NewHexColor(0x8a8a8a),
+		Hint:          tcell.NewHexColor(0x005faf),
+		Error:         tcell.NewHexColor(0xd70000),
+		Accent:        tcell.NewHexColor(0xaf5f00),
+		Success:       tcell.NewHexColor(0x008700),
+	},
+	{
+		Name:          "high-contrast",
+		Background:    tcell.ColorBlack,
+		Text:          tcell.ColorWhite,
+		Secondary:     tcell.ColorWhite,
+		Border:        tcell.ColorYellow,
+		Title:         tcell.ColorYellow,
+		Selection:     tcell.ColorYellow,
+		SelectionText: tcell.ColorBlack,
+		Completed:     tcell.ColorAqua,
+		Hint:          tcell.ColorAqua,
+		Error:         tcell.ColorRed,
+		Accent:        tcell.ColorYellow,
+		Success:       tcell.ColorLime,
+	},
+}
+
+// Tag 颜色对应的tview样式标签, 如 "[#ff0000]"
+func Tag(c tcell.Color) string {
+	if !c.Valid() {
+		return "[-]"
+	}
+	return fmt.Sprintf("[#%06x]", c.Hex())
+}
+
+// ApplyBox 边框, 标题和背景
+func (t *Theme) ApplyBox(b *tview.Box) {
+	b.SetBackgroundColor(t.Background)
+	b.SetBorderColor(t.Border)
+	b.SetTitleColor(t.Title)
+}
+
+// ApplyList 列表
+func (t *Theme) ApplyList(l *tview.List) {
+	t.ApplyBox(l.Box)
+	l.SetMainTextColor(t.Text).
+		SetSecondaryTextColor(t.Secondary).
+		SetShortcutColor(t.Accent).
+		SetSelectedTextColor(t.SelectionText).
+		SetSelectedBackgroundColor(t.Selection)
+}
+
+// ApplyTextView 文本
+func (t *Theme) ApplyTextView(v *tview.TextView) {
+	t.ApplyBox(v.Box)
+	v.SetTextColor(t.Text)
+}
+
+// ApplyInputField 输入框
+func (t *Theme) ApplyInputField(f *tview.InputField) {
+	t.ApplyBox(f.Box)
+	f.SetLabelColor(t.Accent).
+		SetFieldBackgroundColor(t.Selection).
+		SetFieldTextColor(t.SelectionText).
+		SetPlaceholderTextColor(t.Secondary)
+}
+
+// ApplyTextArea 多行输入框
+func (t *Theme) ApplyTextArea(a *tview.TextArea) {
+	t.ApplyBox(a.Box)
+	a.SetTextStyle(tcell.StyleDefault.Background(t.Background).Foreground(t.Text)).
+		SetSelectedStyle(tcell.StyleDefault.Background(t.Selection).Foreground(t.SelectionText)).
+		SetPlaceholderStyle(tcell.StyleDefault.Background(t.Background).Foreground(t.Secondary))
+}
+
+// ApplyForm 表单
+func (t *Theme) ApplyForm(f *tview.Form) {
+	t.ApplyBox(f.Box)
+	f.SetLabelColor(t.Accent).
+		SetFieldBackgroundColor(t.Selection).
+		SetFieldTextColor(t.SelectionText).
+		SetButtonBackgroundColor(t.Selection).
+		SetButtonTextColor(t.SelectionText)
+}
+
+// applyStyles 设置tview的全局样式, 影响之后新建的控件
+func (t *Theme) applyStyles() {
+	tview.Styles.PrimitiveBackgroundColor = t.Background
+	tview.Styles.ContrastBackgroundColor = t.Selection
+	tview.Styles.MoreContrastBackgroundColor = t.Selection
+	tview.Styles.BorderColor = t.Border
+	tview.Styles.TitleColor = t.Title
+	tview.Styles.GraphicsColor = t.Border
+	tview.Styles.PrimaryTextColor = t.Text
+	tview.Styles.SecondaryTextColor = t.Accent
+	tview.Styles.TertiaryTextColor = t.Secondary
+	tview.Styles.InverseTextColor = t.SelectionText
+	tview.Styles.ContrastSecondaryTextColor = t.SelectionText
+}
+
+// themeFile 主题文件, 颜色为名称或 #rrggbb, 未设置的颜色继承base主题
+type themeFile struct {
+	Name          string `yaml:"name"`
+	Base          string `yaml:"base"`
+	Background    string `yaml:"background"`
+	Text          string `yaml:"text"`
+	Secondary     string `yaml:"secondary"`
+	Border        string `yaml:"border"`
+	Title         string `yaml:"title"`
+	Selection     string `yaml:"selection"`
+	SelectionText string `yaml:"selectionText"`
+	Completed     string `yaml:"completed"`
+	Hint          string `yaml:"hint"`
+	Error         string `yaml:"error"`
+	Accent        string `yaml:"accent"`
+	Success       string `yaml:"success"`
+}
+
+// Themes 主题管理, 切换主题时通知订阅者
+type Themes struct {
+	themes    map[string]*Theme
+	names     []string
+	current   *Theme
+	listeners []*themeListener
+
+	logger *slog.Logger
+}
+
+// themeListener 主题变化的订阅, 按指针取消, 同一个函数可以订阅多次
+type themeListener struct {
+	fn func(*Theme)
+}
+
+// NewThemes 新建, 包含内置主题, 默认使用第一个
+func NewThemes(logger *slog.Logger) *Themes {
+	t := &Themes{
+		themes: map[string]*Theme{},
+		logger: logger.With("module", "ui-themes"),
+	}
+	for _, theme := range builtinThemes {
+		t.add(theme)
+	}
+	t.current = builtinThemes[0]
+	return t
+}
+
+func (t *Themes) add(theme *Theme) {
+	if _, ok := t.themes[theme.Name]; !ok {
+		t.names = append(t.names, theme.Name)
+	}
+	t.themes[theme.Name] = theme
+}
+
+// Load 读取目录中的用户主题, 目录不存在时不处理.
+// 有问题的主题文件被跳过, 其他主题照常读取, 返回的错误包含跳过的文件
+func (t *Themes) Load(dir string) error {
+	if dir == "" {
+		return nil
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(files)
+
+	var errs []error
+	for _, file := range files {
+		theme, err := t.loadFile(file)
+		if err != nil {
+			t.logger.Warn(fmt.Sprintf("skip theme file: %s, error: %s.", file, err))
+			errs = append(errs, fmt.Errorf("%s: %w", file, err))
+			continue
+		}
+		t.logger.Debug(fmt.Sprintf("load theme: %s, from: %s.", theme.Name, file))
+		t.add(theme)
+	}
+	return errors.Join(errs...)
+}
+
+func (t *Themes) loadFile(file string) (*Theme, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var f themeFile
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+	if f.Name == "" {
+		f.Name = strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+	}
+	if f.Base == "" {
+		f.Base = builtinThemes[0].Name
+	}
+	base, ok := t.themes[f.Base]
+	if !ok {
+		return nil, fmt.Errorf("unknown base theme %q", f.Base)
+	}
+
+	theme := *base
+	theme.Name = f.Name
+	for _, c := range []struct {
+		value string
+		color *tcell.Color
+	}{
+		{f.Background, &theme.Background},
+		{f.Text, &theme.Text},
+		{f.Secondary, &theme.Secondary},
+		{f.Border, &theme.Border},
+		{f.Title, &theme.Title},
+		{f.Selection, &theme.Selection},
+		{f.SelectionText, &theme.SelectionText},
+		{f.Completed, &theme.Completed},
+		{f.Hint, &theme.Hint},
+		{f.Error, &theme.Error},
+		{f.Accent, &theme.Accent},
+		{f.Success, &theme.Success},
+	} {
+		if c.value == "" {
+			continue
+		}
+		color := tcell.GetColor(strings.ToLower(c.value))
+		if color == tcell.ColorDefault {
+			return nil, fmt.Errorf("invalid color %q", c.value)
+		}
+		*c.color = color
+	}
+	return &theme, nil
+}
+
+// Names 所有主题名称
+func (t *Themes) Names() []string {
+	return t.names
+}
+
+// Current 当前主题
+func (t *Themes) Current() *Theme {
+	return t.current
+}
+
+// Use 切换主题
+func (t *Themes) Use(name string) error {
+	theme, ok := t.themes[name]
+	if !ok {
+		return fmt.Errorf("unknown theme %q", name)
+	}
+	t.logger.Debug(fmt.Sprintf("use theme: %s.", name))
+	t.current = theme
+	theme.applyStyles()
+	for _, l := range slices.Clone(t.listeners) {
+		l.fn(theme)
+	}
+	return nil
+}
+
+// Next 切换到下一个主题
+func (t *Themes) Next() {
+	for i, name := range t.names {
+		if name == t.current.Name {
+			_ = t.Use(t.names[(i+1)%len(t.names)])
+			return
+		}
+	}
+}
+
+// OnChange 订阅主题变化, 订阅时立即以当前主题调用一次. 返回取消订阅的函数, 可以多次调用
+func (t *Themes) OnChange(fn func(*Theme)) (unsubscribe func()) {
+	l := &themeListener{fn: fn}
+	t.listeners = append(t.listeners, l)
+	fn(t.current)
+	return func() {
+		t.listeners = slices.DeleteFunc(t.listeners, func(other *themeListener) bool { return other == l })
+	}
+}
@@ -0,0 +1,42 @@
+package ui
+
+import "testing"
+
+func TestRemovePageUnsubscribesTheme(t *testing.T) {
+	a := NewApp(testLogger())
+	list, other := newListPage("list"), newListPage("other")
+	calls := map[string]int{}
+	for _, p := range []Page{list, other} {
+		if err := a.Registry.Register(p); err != nil {
+			t.Fatal(err)
+		}
+		name := p.Info().Name
+		a.OnThemeChange(name, func(*Theme) { calls[name]++ })
+	}
+	if err := a.InitPages("list", KeymapConfig{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := a.UpdatePages([]Page{list}, KeymapConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	a.Themes.Next()
+	// 订阅时调用一次, 切换主题时只有留下的页面再调用
+	if calls["list"] != 2 || calls["other"] != 1 {
+		t.Fatalf("calls = %v, want list 2, other 1", calls)
+	}
+}
+
+func TestThemesUnsubscribe(t *testing.T) {
+	themes := NewThemes(testLogger())
+	var first, second int
+	unsubscribe := themes.OnChange(func(*Theme) { first++ })
+	themes.OnChange(func(*Theme) { second++ })
+
+	unsubscribe()
+	unsubscribe()
+	themes.Next()
+	if first != 1 || second != 2 {
+		t.Fatalf("calls = %d, %d, want 1, 2", first, second)
+	}
+}
@@ -0,0 +1,371 @@
+// Package uitest 在tcell的模拟屏幕上运行应用, 用于界面测试.
+//
+// 用法:
+//
+//	func TestAddTask(t *testing.T) {
+//		h := uitest.New(t, view.Config{Tools: []string{"todo-list"}})
+//		h.Press("F3")
+//		h.Type("buy milk")
+//		h.Press("Enter")
+//		h.WaitForText("buy milk")
+//		h.AssertGolden("add_task")
+//	}
+//
+// 快照保存在测试目录的testdata下, 用 go test -update 重新生成.
+package uitest
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"kongtools/internal/i18n"
+	"kongtools/internal/ui"
+	"kongtools/internal/view"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/mattn/go-runewidth"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// 默认设置
+const (
+	DefaultWidth  = 100
+	DefaultHeight = 30
+
+	settleTimeout = 2 * time.Second       // 等待重绘的最长时间
+	quietPeriod   = 60 * time.Millisecond // 没有重绘的时间超过它认为界面已稳定
+	pollInterval  = 10 * time.Millisecond // 检查重绘和屏幕内容的间隔
+	startTimeout  = 5 * time.Second       // 等待第一次绘制的最长时间
+	stopTimeout   = 5 * time.Second       // 等待应用退出的最长时间
+	clickInterval = 20 * time.Millisecond // 鼠标按下和松开之间的间隔
+)
+
+// defaultMasks 快照中每次运行都会变化的内容
+var defaultMasks = []Mask{
+	{regexp.MustCompile(`\d{4}-\d{2}-\d{2}`), "YYYY-MM-DD"},
+	{regexp.MustCompile(`\d{2}:\d{2}:\d{2}`), "hh:mm:ss"},
+}
+
+// Mask 比较快照前把匹配的内容替换为Replace
+type Mask struct {
+	Pattern *regexp.Regexp
+	Replace string
+}
+
+// Harness 在模拟屏幕上运行的应用
+type Harness struct {
+	App    *view.App
+	Screen tcell.SimulationScreen
+
+	t      testing.TB
+	draws  atomic.Int64 // 完成的绘制次数
+	masks  []Mask
+	done   chan error
+	closed bool
+}
+
+// New 在width x height的模拟屏幕上启动应用, 测试结束时自动退出.
+// 任务保存在临时目录, 不恢复会话状态, 没有指定语言时使用英文.
+func New(t testing.TB, cfg view.Config) *Harness {
+	t.Helper()
+	return NewWithSize(t, cfg, DefaultWidth, DefaultHeight)
+}
+
+// NewWithSize 在指定大小的模拟屏幕上启动应用
+func NewWithSize(t testing.TB, cfg view.Config, width, height int) *Harness {
+	t.Helper()
+	if cfg.TasksSavePath == "" {
+		cfg.TasksSavePath = filepath.Join(t.TempDir(), "tasks.json")
+	}
+	cfg.Session = view.SessionConfig{}
+	// 快照不能随开发者的LANG变化
+	if cfg.Language == "" {
+		cfg.Language = i18n.English
+	}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	h := &Harness{
+		App:    view.NewApp(logger, cfg),
+		Screen: tcell.NewSimulationScreen("UTF-8"),
+		t:      t,
+		masks:  defaultMasks,
+		done:   make(chan error, 1),
+	}
+	h.App.SetScreen(h.Screen) // 会初始化屏幕, 之后才能设置大小
+	h.Screen.SetSize(width, height)
+	if err := h.App.Init(); err != nil {
+		t.Fatalf("init app: %s", err)
+	}
+
+	// 在原有的绘制之后计数
+	afterDraw := h.App.GetAfterDrawFunc()
+	h.App.SetAfterDrawFunc(func(screen tcell.Screen) {
+		if afterDraw != nil {
+			afterDraw(screen)
+		}
+		h.draws.Add(1)
+	})
+
+	go func() {
+		h.done <- h.App.Run()
+	}()
+	t.Cleanup(h.Stop)
+
+	deadline := time.Now().Add(startTimeout)
+	for h.draws.Load() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("app did not draw within %s", startTimeout)
+		}
+		time.Sleep(pollInterval)
+	}
+	h.Settle()
+	return h
+}
+
+// AddMask 比较快照前额外替换的内容
+func (h *Harness) AddMask(pattern, replace string) {
+	h.masks = append(h.masks, Mask{regexp.MustCompile(pattern), replace})
+}
+
+// Stop 退出应用并等待结束, 可以重复调用
+func (h *Harness) Stop() {
+	if h.closed {
+		return
+	}
+	h.closed = true
+	h.App.Stop()
+	select {
+	case err := <-h.done:
+		if err != nil {
+			h.t.Errorf("run app: %s", err)
+		}
+	case <-time.After(stopTimeout):
+		h.t.Errorf("app did not stop within %s", stopTimeout)
+	}
+}
+
+// Settle 等待界面处理完已发送的事件并完成重绘
+func (h *Harness) Settle() {
+	h.t.Helper()
+	deadline := time.Now().Add(settleTimeout)
+	last := h.draws.Load()
+	quietSince := time.Now()
+	for time.Now().Before(deadline) {
+		time.Sleep(pollInterval)
+		if n := h.draws.Load(); n != last {
+			last = n
+			quietSince = time.Now()
+			continue
+		}
+		if time.Since(quietSince) >= quietPeriod {
+			return
+		}
+	}
+}
+
+// Do 在UI goroutine中执行f, 用于读取或修改控件
+func (h *Harness) Do(f func()) {
+	h.App.QueueUpdateDraw(f)
+	h.Settle()
+}
+
+// Press 依次发送按键并等待重绘, 按键写法与按键配置相同, 如 "Ctrl+P", "Enter", "g g"
+func (h *Harness) Press(keys ...string) {
+	h.t.Helper()
+	for _, k := range keys {
+		for _, f := range strings.Fields(k) {
+			event, err := ui.ParseKeyEvent(f)
+			if err != nil {
+				h.t.Fatalf("press %q: %s", k, err)
+			}
+			h.Screen.PostEventWait(event)
+		}
+	}
+	h.Settle()
+}
+
+// Type 逐个字符输入文本并等待重绘
+func (h *Harness) Type(text string) {
+	for _, r := range text {
+		h.Screen.PostEventWait(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+	}
+	h.Settle()
+}
+
+// Click 在(x, y)单击左键并等待重绘
+func (h *Harness) Click(x, y int) {
+	h.Screen.PostEventWait(tcell.NewEventMouse(x, y, tcell.Button1, tcell.ModNone))
+	time.Sleep(clickInterval)
+	h.Screen.PostEventWait(tcell.NewEventMouse(x, y, tcell.ButtonNone, tcell.ModNone))
+	h.Settle()
+}
+
+// ClickText 单击屏幕上第一处text的第一个字符
+func (h *Harness) ClickText(text string) {
+	h.t.Helper()
+	x, y, ok := h.Find(text)
+	if !ok {
+		h.t.Fatalf("click %q: text not on screen\n%s", text, h.Text())
+	}
+	h.Click(x, y)
+}
+
+// Drag 按住左键从(x1, y1)拖动到(x2, y2)并等待重绘
+func (h *Harness) Drag(x1, y1, x2, y2 int) {
+	h.Screen.PostEventWait(tcell.NewEventMouse(x1, y1, tcell.Button1, tcell.ModNone))
+	time.Sleep(clickInterval)
+	h.Screen.PostEventWait(tcell.NewEventMouse(x2, y2, tcell.Button1, tcell.ModNone))
+	time.Sleep(clickInterval)
+	h.Screen.PostEventWait(tcell.NewEventMouse(x2, y2, tcell.ButtonNone, tcell.ModNone))
+	h.Settle()
+}
+
+// Resize 改变屏幕大小并等待重绘
+func (h *Harness) Resize(width, height int) {
+	h.Screen.SetSize(width, height)
+	h.Screen.PostEventWait(tcell.NewEventResize(width, height))
+	h.Settle()
+}
+
+// row 第y行的文本和每个字节所在的列.
+// 宽字符的第二格不会重绘, 可能残留旧内容, 按字符宽度跳过.
+func row(cells []tcell.SimCell, width, y int) (string, []int) {
+	var b strings.Builder
+	columns := make([]int, 0, width)
+	for x := 0; x < width; x++ {
+		text := " "
+		runes := cells[y*width+x].Runes
+		if len(runes) > 0 {
+			text = string(runes)
+		}
+		b.WriteString(text)
+		for range []byte(text) {
+			columns = append(columns, x)
+		}
+		if len(runes) > 0 && runewidth.RuneWidth(runes[0]) == 2 {
+			x++
+		}
+	}
+	return b.String(), columns
+}
+
+// Lines 屏幕内容, 每行去掉末尾的空格
+func (h *Harness) Lines() []string {
+	cells, width, height := h.Screen.GetContents()
+	lines := make([]string, height)
+	for y := 0; y < height; y++ {
+		text, _ := row(cells, width, y)
+		lines[y] = strings.TrimRight(text, " ")
+	}
+	return lines
+}
+
+// Text 屏幕内容
+func (h *Harness) Text() string {
+	return strings.Join(h.Lines(), "\n") + "\n"
+}
+
+// Find 屏幕上第一处text的位置
+func (h *Harness) Find(text string) (x, y int, ok bool) {
+	cells, width, height := h.Screen.GetContents()
+	for y := 0; y < height; y++ {
+		line, columns := row(cells, width, y)
+		if i := strings.Index(line, text); i >= 0 {
+			return columns[i], y, true
+		}
+	}
+	return 0, 0, false
+}
+
+// Contains 屏幕上是否有text
+func (h *Harness) Contains(text string) bool {
+	_, _, ok := h.Find(text)
+	return ok
+}
+
+// WaitForText 等待text出现在屏幕上, 超时后测试失败
+func (h *Harness) WaitForText(text string) {
+	h.t.Helper()
+	deadline := time.Now().Add(settleTimeout)
+	for !h.Contains(text) {
+		if time.Now().After(deadline) {
+			h.t.Fatalf("text %q not on screen after %s\n%s", text, settleTimeout, h.Text())
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+// WaitForNoText 等待text从屏幕上消失, 超时后测试失败
+func (h *Harness) WaitForNoText(text string) {
+	h.t.Helper()
+	deadline := time.Now().Add(settleTimeout)
+	for h.Contains(text) {
+		if time.Now().After(deadline) {
+			h.t.Fatalf("text %q still on screen after %s\n%s", text, settleTimeout, h.Text())
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+// Snapshot 替换掉变化内容后的屏幕内容
+func (h *Harness) Snapshot() string {
+	text := h.Text()
+	for _, m := range h.masks {
+		text = m.Pattern.ReplaceAllString(text, m.Replace)
+	}
+	return text
+}
+
+// AssertGolden 比较屏幕内容和testdata/name.golden, -update时写入快照
+func (h *Harness) AssertGolden(name string) {
+	h.t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	got := h.Snapshot()
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			h.t.Fatalf("update golden: %s", err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			h.t.Fatalf("update golden: %s", err)
+		}
+		return
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		h.t.Fatalf("read golden: %s (run go test -update to create it)", err)
+	}
+	if want := string(data); got != want {
+		h.t.Errorf("screen differs from %s:\n%s", path, diff(want, got))
+	}
+}
+
+// diff 逐行比较, 列出不同的行
+func diff(want, got string) string {
+	wantLines := strings.Split(want, "\n")
+	gotLines := strings.Split(got, "\n")
+	var b strings.Builder
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w != g {
+			fmt.Fprintf(&b, "line %d:\n- %s\n+ %s\n", i+1, w, g)
+		}
+	}
+	return b.String()
+}
@@ -1,40 +1,104 @@
 package view
 
 import (
+	"kongtools/internal/i18n"
+	"kongtools/internal/task"
 	"kongtools/internal/ui"
 	"log/slog"
+	"time"
 
 	"github.com/rivo/tview"
 )
 
 type Config struct {
 	TasksSavePath string
+	Tools         []string // 启用的工具及顺序, 为空时启用全部
+	Home          string   // 启动时显示的页面
+	Language      string   // 界面语言, 为空或auto时按LANG选择
+	Welcome       WelcomeConfig
+	Todo          TodoConfig
+	Board         BoardConfig
+	Keys          ui.KeymapConfig
+	Theme         ui.ThemeConfig
+	Session       SessionConfig
+}
+
+// SessionConfig 会话状态配置
+type SessionConfig struct {
+	Restore bool   // 是否在下次启动时恢复
+	Path    string // 状态文件, 为空时在XDG状态目录下
 }
 
 const DefaultConfig = `app:
-  tasksSavePath: tasks.json
+  tasksSavePath: "" # default is $XDG_DATA_HOME/kongtools/tasks.json
+  tools: [welcome, todo-list, board, calendar, stats, keys]
+  home: welcome # page to start on, may have params, e.g. todo-list?due=2024-05-01
+  language: auto # auto (from LANG), en or zh
+  welcome:
+    sections: [banner, clock, today, tip, recent]
+    refreshInterval: 1s
+  todo:
+    titleLimit: 80 # max display width of a task title, CJK characters and emoji count 2
+  board:
+    statuses: [todo, doing, done]
+    wipLimits:
+      doing: 3
+  theme:
+    name: dark # dark, light, high-contrast or a theme in dir
+    dir: themes # next to the config file
+  session:
+    restore: true # restore the last page, selection and input on start
+    path: "" # default is $XDG_STATE_HOME/kongtools/state.json
+  keys:
+    preset: default # default or vim
+    bindings: {}
+    # bindings:
+    #   global:
+    #     quit: [Ctrl+Q]
+    #   todo-list:
+    #     complete: [Space, x]
+    #     delete: [d d]
 `
 
 // App 应用视图
 type App struct {
 	*ui.App
-	Content *ui.Pages
+	Store *task.Store
+
+	workflow  task.Workflow
+	recent    []string // 最近使用的页面, 最近的在前
+	startPage string   // 命令行指定的启动页面
 
-	cfg    Config
-	logger *slog.Logger
+	cfg        Config
+	baseLogger *slog.Logger
+	logger     *slog.Logger
 }
 
-// NewApp 新建
+// recentLimit 最多记录的最近使用页面数
+const recentLimit = 5
+
+// NewApp 新建, 界面文本使用配置的语言
 func NewApp(logger *slog.Logger, cfg Config) *App {
+	// 控件在新建时设置文本, 语言要最先设置
+	if err := i18n.SetLanguage(cfg.Language); err != nil {
+		logger.Warn("set language error", slog.String("error", err.Error()))
+	}
+	if err := i18n.Check(); err != nil {
+		logger.Warn("check message catalog error", slog.String("error", err.Error()))
+	}
+
 	a := App{
-		App:     ui.NewApp(logger),
-		Content: ui.NewPages(logger),
-		cfg:     cfg,
-		logger:  logger.With("module", "view-app"),
+		App:        ui.NewApp(logger),
+		Store:      task.NewStore(cfg.TasksSavePath),
+		workflow:   task.NewWorkflow(cfg.Board.Statuses),
+		cfg:        cfg,
+		baseLogger: logger,
+		logger:     logger.With("module", "view-app"),
 	}
 
-	a.Views()["welcome"] = NewWelcome(logger)
-	a.Views()["todo-list"] = NewTodoList(logger, cfg.TasksSavePath)
+	if err := a.Store.Load(); err != nil {
+		a.logger.Error("load tasks error", slog.String("error", err.Error()))
+	}
 
 	return &a
 }
@@ -46,28 +110,56 @@ func (a *App) Init() error {
 
 	a.App.Init()
 
-	a.Menu().AddItem("Welcome", "Welcome page", rune('w'), func() {
-		a.logger.Debug("switch to welcome page ...")
-		a.Content.SwitchToPage("welcome")
-	})
-
-	a.Menu().AddItem("Todo List", "Todo list page", rune('t'), func() {
-		a.logger.Debug("switch to todo list page ...")
-		a.Content.SwitchToPage("todo-list")
-	})
+	if err := a.InitTheme(a.cfg.Theme); err != nil {
+		return err
+	}
+	if err := a.registerTools(); err != nil {
+		return err
+	}
+	home := a.initSession()
 
 	// a.TestSwitchPagesAndContent() // test switch pages and content logic
 
-	a.Menu().AddItem("Quit", "Press to exit", rune('q'), func() {
-		a.logger.Debug("quit app ...")
-		a.Application.Stop()
-	})
+	a.SetPageChangedFunc(a.addRecent)
+	if err := a.InitPages(home, a.cfg.Keys); err != nil {
+		return err
+	}
 
 	a.flexLayout()
 
 	return nil
 }
 
+// SetStartPage 设置启动页面, 优先于上次的页面和配置
+func (a *App) SetStartPage(route string) {
+	a.startPage = route
+}
+
+// initSession 读取会话状态, 返回启动页面
+func (a *App) initSession() string {
+	if a.cfg.Session.Restore {
+		path := a.cfg.Session.Path
+		if path == "" {
+			var err error
+			if path, err = ui.DefaultStatePath(); err != nil {
+				a.logger.Warn("find state path error", slog.String("error", err.Error()))
+			}
+		}
+		// 状态文件损坏时不影响启动
+		if err := a.InitSession(path); err != nil {
+			a.logger.Warn("load session error", slog.String("error", err.Error()))
+		}
+	}
+
+	switch {
+	case a.startPage != "":
+		return a.startPage
+	case a.Session.Route() != "":
+		return a.Session.Route()
+	}
+	return a.cfg.Home
+}
+
 // TestSwitchPagesAndContent 用来测试页面和内容切换的方法
 func (a *App) TestSwitchPagesAndContent() {
 	// 用来测试page切换的,可删
@@ -111,22 +203,26 @@ func (a *App) Run() error {
 	a.logger.Debug("run app start ...")
 	defer a.logger.Debug("run app end ...")
 
-	// 运行时将各个功能page加到Content中
-	a.Content.AddPage("welcome", a.Welcome(), true, true)
-	a.Content.AddPage("todo-list", a.TodoList(), true, false)
+	defer a.shutdown()
 
 	a.Main.SwitchToPage("main")
-	return a.Application.Run()
+	return a.App.Run()
 }
 
-// flexLayout app flex布局
-func (a *App) flexLayout() {
-	main := tview.NewFlex().SetDirection(tview.FlexColumn)
-
-	main.AddItem(a.Menu(), 0, 1, true)  // SideBar
-	main.AddItem(a.Content, 0, 3, true) // Body
+// shutdown 关闭页面并保存未保存的任务
+func (a *App) shutdown() {
+	if err := a.SaveSession(); err != nil {
+		a.logger.Error("save session error", slog.String("error", err.Error()))
+	}
+	a.ShutdownPages()
+	if err := a.Store.Flush(); err != nil {
+		a.logger.Error("save tasks error", slog.String("error", err.Error()))
+	}
+}
 
-	a.Main.AddPage("main", main, true, true)
+// flexLayout 把布局加入Main
+func (a *App) flexLayout() {
+	a.Main.AddPage("main", a.Layout, true, true)
 }
 
 // buildContent 内容
@@ -140,12 +236,37 @@ func (a *App) buildContent() tview.Primitive {
 	return content
 }
 
-// Welcome 欢迎页
-func (a *App) Welcome() *Welcome {
-	return a.Views()["welcome"].(*Welcome)
+// addRecent 记录最近使用的页面
+func (a *App) addRecent(name string) {
+	recent := []string{name}
+	for _, r := range a.recent {
+		if r != name && len(recent) < recentLimit {
+			recent = append(recent, r)
+		}
+	}
+	a.recent = recent
+}
+
+// recentTitles 最近使用的页面名称, 不含欢迎页
+func (a *App) recentTitles() []string {
+	var titles []string
+	for _, name := range a.recent {
+		if p, ok := a.Registry.Page(name); ok && name != "welcome" {
+			titles = append(titles, p.Info().Title)
+		}
+	}
+	return titles
 }
 
-// (page)TodoList 任务列表
-func (a *App) TodoList() *TodoList {
-	return a.Views()["todo-list"].(*TodoList)
+// openDay 打开按day过滤的任务列表
+func (a *App) openDay(day time.Time) {
+	p, ok := a.Registry.Page("todo-list")
+	if !ok {
+		a.logger.Warn("todo list page is disabled")
+		return
+	}
+
+	a.logger.Debug("switch to todo list page ...", slog.String("due", day.Format(time.DateOnly)))
+	a.Navigate("todo-list", map[string]string{"due": day.Format(time.DateOnly)})
+	a.SetFocus(p.Primitive())
 }
@@ -0,0 +1,318 @@
+package view
+
+import (
+	"encoding/json"
+	"fmt"
+	"kongtools/internal/i18n"
+	"kongtools/internal/task"
+	"kongtools/internal/ui"
+	"log/slog"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// BoardConfig 看板配置
+type BoardConfig struct {
+	Statuses  []string
+	WipLimits map[string]int
+}
+
+func init() {
+	registerTool("board", func(a *App, logger *slog.Logger) ui.Page {
+		return NewBoard(logger, a.App, a.Store, a.workflow, a.cfg.Board.WipLimits)
+	})
+}
+
+// Board 看板
+type Board struct {
+	*tview.Flex
+	columns []*tview.List
+
+	// data
+	store    *task.Store
+	workflow task.Workflow
+	limits   map[string]int
+	cards    [][]int // 每一列的卡片对应的任务序号
+
+	// control
+	focused  int
+	dragFrom int // 拖动开始的列, -1表示没有拖动
+
+	app    *ui.App
+	theme  *ui.Theme
+	logger *slog.Logger
+}
+
+// NewBoard 新建
+func NewBoard(logger *slog.Logger, app *ui.App, store *task.Store, workflow task.Workflow, limits map[string]int) *Board {
+	b := &Board{
+		Flex:     tview.NewFlex(),
+		store:    store,
+		workflow: workflow,
+		limits:   limits,
+		dragFrom: -1,
+		app:      app,
+		logger:   logger.With("module", "view-board"),
+	}
+
+	columns := tview.NewFlex().SetDirection(tview.FlexColumn)
+	for range workflow.Statuses {
+		column := tview.NewList().ShowSecondaryText(false)
+		column.SetBorder(true)
+		b.columns = append(b.columns, column)
+		columns.AddItem(column, 0, 1, len(b.columns) == 1)
+	}
+
+	b.SetDirection(tview.FlexRow).
+		AddItem(columns, 0, 1, true)
+	b.SetBorder(true).
+		SetTitle(i18n.T("board.title")).
+		SetTitleAlign(tview.AlignCenter)
+
+	app.OnThemeChange(b.Info().Name, b.applyTheme)
+	store.Subscribe("board", b.refresh)
+
+	return b
+}
+
+// Info 页面信息
+func (b *Board) Info() ui.PageInfo {
+	return ui.PageInfo{Name: "board", Title: i18n.T("board.title"), Description: i18n.T("board.description"), Shortcut: 'b'}
+}
+
+// Primitive 页面内容
+func (b *Board) Primitive() tview.Primitive {
+	return b
+}
+
+// Init 注册按键动作
+func (b *Board) Init() error {
+	keymap := b.app.Keymap
+	keymap.Register("board", "left", i18n.T("action.board.left"), func() {
+		b.focusColumn(b.focused - 1)
+	}, "h", "Left")
+	keymap.Register("board", "right", i18n.T("action.board.right"), func() {
+		b.focusColumn(b.focused + 1)
+	}, "l", "Right")
+	keymap.Register("board", "move-left", i18n.T("action.board.move-left"), func() {
+		if b.MoveCard(b.focused, b.focused-1) {
+			b.focusColumn(b.focused - 1)
+		}
+	}, "H", "Shift+Left")
+	keymap.Register("board", "move-right", i18n.T("action.board.move-right"), func() {
+		if b.MoveCard(b.focused, b.focused+1) {
+			b.focusColumn(b.focused + 1)
+		}
+	}, "L", "Shift+Right")
+	return nil
+}
+
+// OnFocus 切换到该页面时刷新
+func (b *Board) OnFocus() {
+	b.refresh()
+}
+
+// Shutdown 退出
+func (b *Board) Shutdown() {
+}
+
+// Focus 获得焦点时聚焦到上次的列
+func (b *Board) Focus(delegate func(p tview.Primitive)) {
+	delegate(b.columns[b.focused])
+}
+
+// boardState 会话状态
+type boardState struct {
+	Focused int   `json:"focused"`
+	Current []int `json:"current"` // 每一列选中的卡片
+}
+
+// SaveState 保存当前列和每一列选中的卡片
+func (b *Board) SaveState() any {
+	state := boardState{Focused: b.focused}
+	for _, column := range b.columns {
+		state.Current = append(state.Current, column.GetCurrentItem())
+	}
+	return state
+}
+
+// RestoreState 恢复状态
+func (b *Board) RestoreState(data []byte) error {
+	var state boardState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+	if state.Focused >= 0 && state.Focused < len(b.columns) {
+		b.focused = state.Focused
+	}
+	for col, current := range state.Current {
+		if col < len(b.columns) && current < b.columns[col].GetItemCount() {
+			b.columns[col].SetCurrentItem(current)
+		}
+	}
+	return nil
+}
+
+func (b *Board) applyTheme(theme *ui.Theme) {
+	b.theme = theme
+	theme.ApplyBox(b.Box)
+	for _, column := range b.columns {
+		theme.ApplyList(column)
+	}
+	b.refresh()
+}
+
+// refresh 按任务状态重建各列
+func (b *Board) refresh() {
+	b.cards = make([][]int, len(b.columns))
+	for i, item := range b.store.Tasks() {
+		col := b.workflow.Index(b.workflow.Status(item))
+		b.cards[col] = append(b.cards[col], i)
+	}
+
+	items := b.store.Tasks()
+	for col, column := range b.columns {
+		current := column.GetCurrentItem()
+		column.Clear()
+		for _, index := range b.cards[col] {
+			column.AddItem(cardText(items[index], b.theme), "", 0, nil)
+		}
+		if current < column.GetItemCount() {
+			column.SetCurrentItem(current)
+		}
+		b.updateColumnTitle(col)
+	}
+}
+
+func cardText(item Task, theme *ui.Theme) string {
+	text := tview.Escape(item.Title)
+	if len(item.Tags) > 0 {
+		text += " " + ui.Tag(theme.Secondary) + tview.Escape("#"+strings.Join(item.Tags, " #")) + "[-]"
+	}
+	if item.Running() {
+		text += " " + ui.Tag(theme.Accent) + "⏱[-]"
+	}
+	return text
+}
+
+func (b *Board) updateColumnTitle(col int) {
+	status := b.workflow.Statuses[col]
+	count := len(b.cards[col])
+	title := fmt.Sprintf("%s (%d)", status, count)
+
+	column := b.columns[col]
+	column.SetTitleColor(b.theme.Title)
+	if limit, ok := b.limits[status]; ok && limit > 0 {
+		title = fmt.Sprintf("%s (%d/%d)", status, count, limit)
+		if count > limit {
+			column.SetTitleColor(b.theme.Error)
+		}
+	}
+	column.SetTitle(title)
+}
+
+// MoveCard 把from列当前的卡片移到to列
+func (b *Board) MoveCard(from, to int) bool {
+	if to < 0 || to >= len(b.columns) || from == to {
+		return false
+	}
+	current := b.columns[from].GetCurrentItem()
+	if current < 0 || current >= len(b.cards[from]) {
+		return false
+	}
+
+	status := b.workflow.Statuses[to]
+	if limit, ok := b.limits[status]; ok && limit > 0 && len(b.cards[to]) >= limit {
+		b.app.Notify(ui.SeverityWarning, i18n.T("board.wip_reached", status, limit))
+		return false
+	}
+
+	index := b.cards[from][current]
+	var item Task
+	b.store.Checkpoint(i18n.T("todo.bulk.move", status))
+	b.store.Update("board", func(tasks []Task) []Task {
+		b.workflow.SetStatus(&tasks[index], status)
+		item = tasks[index]
+		return tasks
+	})
+	b.refresh()
+	recordTask(b.logger, b.store, task.ActionMoved, item)
+
+	// 选中移动后的卡片
+	for i, idx := range b.cards[to] {
+		if idx == index {
+			b.columns[to].SetCurrentItem(i)
+		}
+	}
+	b.logger.Debug("Card moved", slog.Int("task", index), slog.String("status", status))
+
+	scheduleSave(b.logger, b.app, b.store)
+	return true
+}
+
+// focusColumn 切换到第col列
+func (b *Board) focusColumn(col int) {
+	if col < 0 || col >= len(b.columns) {
+		return
+	}
+	b.focused = col
+	b.app.SetFocus(b.columns[col])
+}
+
+// InputHandler 先按按键配置分发, 未处理的交给当前列
+func (b *Board) InputHandler() func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+	return b.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+		if b.app.Keymap.Dispatch("board", event) == nil {
+			return
+		}
+		if handler := b.Flex.InputHandler(); handler != nil {
+			handler(event, setFocus)
+		}
+	})
+}
+
+// MouseHandler 支持在列之间拖动卡片
+func (b *Board) MouseHandler() func(action tview.MouseAction, event *tcell.EventMouse, setFocus func(p tview.Primitive)) (consumed bool, capture tview.Primitive) {
+	return b.WrapMouseHandler(func(action tview.MouseAction, event *tcell.EventMouse, setFocus func(p tview.Primitive)) (consumed bool, capture tview.Primitive) {
+		x, y := event.Position()
+		col := b.columnAt(x, y)
+
+		switch action {
+		case tview.MouseLeftDown:
+			// 先让列表选中点击的卡片
+			consumed, capture = b.Flex.MouseHandler()(action, event, setFocus)
+			if col >= 0 {
+				b.focused = col
+				b.dragFrom = col
+				return true, b
+			}
+			return
+		case tview.MouseLeftUp:
+			if b.dragFrom >= 0 && col >= 0 && col != b.dragFrom {
+				if b.MoveCard(b.dragFrom, col) {
+					b.focused = col
+					setFocus(b.columns[col])
+				}
+			}
+			b.dragFrom = -1
+			return true, nil
+		}
+
+		if b.dragFrom >= 0 {
+			// 拖动中
+			return true, b
+		}
+		return b.Flex.MouseHandler()(action, event, setFocus)
+	})
+}
+
+func (b *Board) columnAt(x, y int) int {
+	for i, column := range b.columns {
+		if column.InRect(x, y) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,26 @@
+package view_test
+
+import (
+	"os"
+	"testing"
+
+	"kongtools/internal/uitest"
+	"kongtools/internal/view"
+)
+
+func TestBoardSaveFailed(t *testing.T) {
+	path := writeTasks(t, "buy milk")
+	h := uitest.New(t, view.Config{TasksSavePath: path, Tools: []string{"board"}})
+	h.Press("F3")
+	h.WaitForText("buy milk")
+	// 任务文件换成目录, 保存会失败
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(path, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	h.Press("L")
+	h.WaitForText("Failed to save tasks")
+}
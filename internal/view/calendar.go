@@ -0,0 +1,144 @@
+package view
+
+import (
+	"encoding/json"
+	"kongtools/internal/i18n"
+	"kongtools/internal/task"
+	"kongtools/internal/ui"
+	"log/slog"
+	"time"
+
+	"github.com/rivo/tview"
+)
+
+func init() {
+	registerTool("calendar", func(a *App, logger *slog.Logger) ui.Page {
+		return NewCalendar(logger, a.App, a.Store, a.openDay)
+	})
+}
+
+// Calendar 按到期日显示任务的日历
+type Calendar struct {
+	*ui.Calendar
+
+	store *task.Store
+
+	theme  *ui.Theme
+	logger *slog.Logger
+}
+
+// NewCalendar 新建, open在选中某天时调用
+func NewCalendar(logger *slog.Logger, app *ui.App, store *task.Store, open func(day time.Time)) *Calendar {
+	c := &Calendar{
+		Calendar: ui.NewCalendar(logger),
+		store:    store,
+		logger:   logger.With("module", "view-calendar"),
+	}
+
+	c.SetEventsFunc(c.tasksOn)
+	c.SetSelectedFunc(func(day time.Time) {
+		c.logger.Debug("open day", slog.String("day", day.Format(time.DateOnly)))
+		open(day)
+	})
+	c.SetBorder(true).
+		SetTitle(i18n.T("calendar.title")).
+		SetTitleAlign(tview.AlignCenter)
+	app.OnThemeChange(c.Info().Name, func(theme *ui.Theme) {
+		c.theme = theme
+		c.ApplyTheme(theme)
+	})
+
+	return c
+}
+
+// Info 页面信息
+func (c *Calendar) Info() ui.PageInfo {
+	return ui.PageInfo{Name: "calendar", Title: i18n.T("calendar.title"), Description: i18n.T("calendar.description"), Shortcut: 'c'}
+}
+
+// Primitive 页面内容
+func (c *Calendar) Primitive() tview.Primitive {
+	return c
+}
+
+// Init 初始化
+func (c *Calendar) Init() error {
+	return nil
+}
+
+// SetParams 页面参数: date 选中的日期, mode 为 month 或 week
+func (c *Calendar) SetParams(params map[string]string) {
+	if text, ok := params["date"]; ok {
+		if day, err := time.ParseInLocation(time.DateOnly, text, time.Local); err == nil {
+			c.SetDate(day)
+		} else {
+			c.logger.Warn("invalid calendar date", slog.String("date", text))
+		}
+	}
+	switch params["mode"] {
+	case "month":
+		c.SetMode(ui.CalendarMonth)
+	case "week":
+		c.SetMode(ui.CalendarWeek)
+	}
+}
+
+// OnFocus 切换到该页面
+func (c *Calendar) OnFocus() {
+}
+
+// calendarState 会话状态
+type calendarState struct {
+	Date string `json:"date"`
+	Week bool   `json:"week"`
+}
+
+// SaveState 保存选中的日期和显示模式
+func (c *Calendar) SaveState() any {
+	return calendarState{
+		Date: c.GetDate().Format(time.DateOnly),
+		Week: c.GetMode() == ui.CalendarWeek,
+	}
+}
+
+// RestoreState 恢复状态
+func (c *Calendar) RestoreState(data []byte) error {
+	var state calendarState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+	params := map[string]string{"date": state.Date, "mode": "month"}
+	if state.Week {
+		params["mode"] = "week"
+	}
+	c.SetParams(params)
+	return nil
+}
+
+// Shutdown 退出
+func (c *Calendar) Shutdown() {
+}
+
+// tasksOn day当天到期的任务
+func (c *Calendar) tasksOn(day time.Time) []string {
+	var events []string
+	now := time.Now()
+	for _, item := range c.store.Tasks() {
+		if !item.DueOn(day) {
+			continue
+		}
+
+		text := tview.Escape(item.Title)
+		if item.Due.Hour() != 0 || item.Due.Minute() != 0 {
+			text = item.Due.Local().Format("15:04 ") + text
+		}
+		switch {
+		case item.Completed:
+			text = ui.Tag(c.theme.Completed) + text + "[-]"
+		case item.Overdue(now):
+			text = ui.Tag(c.theme.Error) + text + "[-]"
+		}
+		events = append(events, text)
+	}
+	return events
+}
@@ -0,0 +1,164 @@
+package view
+
+import (
+	"context"
+	"kongtools/internal/i18n"
+	"kongtools/internal/task"
+	"kongtools/internal/ui"
+	"log/slog"
+	"time"
+
+	"github.com/rivo/tview"
+)
+
+// dashboardDays 统计最近的天数
+const dashboardDays = 30
+
+func init() {
+	registerTool("stats", func(a *App, logger *slog.Logger) ui.Page {
+		return NewDashboard(logger, a.App, a.Store)
+	})
+}
+
+// Dashboard 统计面板
+type Dashboard struct {
+	*tview.Flex
+	summary   *tview.TextView
+	created   *ui.Sparkline
+	completed *ui.Sparkline
+	tags      *ui.BarChart
+
+	store      *task.Store
+	generation int // 每次刷新加一, 丢弃过期的统计结果
+
+	app    *ui.App
+	theme  *ui.Theme
+	logger *slog.Logger
+}
+
+// NewDashboard 新建
+func NewDashboard(logger *slog.Logger, app *ui.App, store *task.Store) *Dashboard {
+	d := &Dashboard{
+		Flex:      tview.NewFlex(),
+		summary:   tview.NewTextView(),
+		created:   ui.NewSparkline(logger),
+		completed: ui.NewSparkline(logger),
+		tags:      ui.NewBarChart(logger),
+		store:     store,
+		app:       app,
+		logger:    logger.With("module", "view-dashboard"),
+	}
+
+	d.summary.SetDynamicColors(true)
+	d.created.SetBorder(true).
+		SetTitle(i18n.T("stats.created", dashboardDays)).
+		SetTitleAlign(tview.AlignLeft)
+	d.completed.SetBorder(true).
+		SetTitle(i18n.T("stats.completed", dashboardDays)).
+		SetTitleAlign(tview.AlignLeft)
+	d.tags.SetBorder(true).
+		SetTitle(i18n.T("stats.tag_time", dashboardDays)).
+		SetTitleAlign(tview.AlignLeft)
+
+	d.SetDirection(tview.FlexRow).
+		AddItem(d.summary, 2, 0, false).
+		AddItem(d.created, 5, 0, false).
+		AddItem(d.completed, 5, 0, false).
+		AddItem(d.tags, 0, 1, false)
+	d.SetBorder(true).
+		SetTitle(i18n.T("stats.title")).
+		SetTitleAlign(tview.AlignCenter)
+
+	app.OnThemeChange(d.Info().Name, d.applyTheme)
+	store.Subscribe("dashboard", d.Refresh)
+
+	return d
+}
+
+func (d *Dashboard) applyTheme(theme *ui.Theme) {
+	d.theme = theme
+	theme.ApplyBox(d.Box)
+	theme.ApplyTextView(d.summary)
+	d.created.ApplyTheme(theme)
+	d.created.SetColor(theme.Accent)
+	d.completed.ApplyTheme(theme)
+	d.completed.SetColor(theme.Success)
+	d.tags.ApplyTheme(theme)
+	d.tags.SetColor(theme.Hint)
+	d.Refresh()
+}
+
+// Refresh 重新统计
+func (d *Dashboard) Refresh() {
+	d.generation++
+	generation := d.generation
+
+	// 读取日志文件较慢, 在后台统计
+	var stats task.Stats
+	d.app.Background.Go("stats", func(context.Context) error {
+		events, err := d.store.Events()
+		stats = task.ComputeStats(d.store.Tasks(), events, dashboardDays, time.Now())
+		return err
+	}, func(err error) {
+		if err != nil {
+			d.logger.Error("read task events error", slog.String("error", err.Error()))
+		}
+		if generation == d.generation {
+			d.render(stats)
+		}
+	})
+}
+
+// render 显示统计结果
+func (d *Dashboard) render(stats task.Stats) {
+	createdTotal, completedTotal := 0, 0
+	created := make([]float64, len(stats.Days))
+	completed := make([]float64, len(stats.Days))
+	for i := range stats.Days {
+		created[i] = float64(stats.Created[i])
+		completed[i] = float64(stats.Completed[i])
+		createdTotal += stats.Created[i]
+		completedTotal += stats.Completed[i]
+	}
+	d.created.SetData(created)
+	d.completed.SetData(completed)
+
+	accent, errColor, success := ui.Tag(d.theme.Accent), ui.Tag(d.theme.Error), ui.Tag(d.theme.Success)
+	d.summary.SetText(i18n.T("stats.summary",
+		accent, stats.Open, errColor, stats.Overdue, accent, createdTotal, success, completedTotal,
+		success, stats.Streak, success, stats.LongestStreak))
+
+	bars := make([]ui.Bar, 0, len(stats.TagTime))
+	for _, row := range stats.TagTime {
+		bars = append(bars, ui.Bar{
+			Label: tview.Escape(row.Group),
+			Value: row.Hours,
+			Text:  task.FormatDuration(row.Duration),
+		})
+	}
+	d.tags.SetBars(bars)
+}
+
+// Info 页面信息
+func (d *Dashboard) Info() ui.PageInfo {
+	return ui.PageInfo{Name: "stats", Title: i18n.T("stats.name"), Description: i18n.T("stats.description"), Shortcut: 's'}
+}
+
+// Primitive 页面内容
+func (d *Dashboard) Primitive() tview.Primitive {
+	return d
+}
+
+// Init 初始化
+func (d *Dashboard) Init() error {
+	return nil
+}
+
+// OnFocus 切换到该页面时刷新
+func (d *Dashboard) OnFocus() {
+	d.Refresh()
+}
+
+// Shutdown 退出
+func (d *Dashboard) Shutdown() {
+}
@@ -0,0 +1,93 @@
+package view
+
+import (
+	"errors"
+	"kongtools/internal/i18n"
+	"kongtools/internal/task"
+	"kongtools/internal/ui"
+	"time"
+
+	"github.com/rivo/tview"
+)
+
+// entryTimeLayout 计时记录的编辑格式
+const entryTimeLayout = "2006-01-02 15:04"
+
+// newEntriesForm 编辑任务计时记录的表单.
+// 开始时间留空表示删除该条记录, 结束时间留空表示计时中.
+func newEntriesForm(item Task, save func([]task.TimeEntry), cancel func(), failed func(error), theme *ui.Theme) *tview.Form {
+	form := tview.NewForm()
+	theme.ApplyForm(form)
+	form.SetBorder(true).
+		SetTitle(i18n.T("entries.title", tview.Escape(item.Title))).
+		SetTitleAlign(tview.AlignLeft)
+
+	entries := append([]task.TimeEntry{}, item.Entries...)
+	addFields := func(i int, e task.TimeEntry) {
+		end := ""
+		if !e.Running() {
+			end = e.End.Local().Format(entryTimeLayout)
+		}
+		form.AddInputField(i18n.T("entries.start", i+1), e.Start.Local().Format(entryTimeLayout), 20, nil, nil)
+		form.AddInputField(i18n.T("entries.end", i+1), end, 20, nil, nil)
+	}
+	for i, e := range entries {
+		addFields(i, e)
+	}
+
+	form.AddButton(i18n.T("entries.add"), func() {
+		now := time.Now()
+		entries = append(entries, task.TimeEntry{Start: now, End: now})
+		addFields(len(entries)-1, entries[len(entries)-1])
+	})
+	form.AddButton(i18n.T("entries.save"), func() {
+		result, err := parseEntriesForm(form, len(entries))
+		if err != nil {
+			failed(err)
+			return
+		}
+		save(result)
+	})
+	form.AddButton(i18n.T("dialog.cancel"), cancel)
+	form.SetCancelFunc(cancel)
+
+	return form
+}
+
+// parseEntriesForm 从表单读取计时记录
+func parseEntriesForm(form *tview.Form, count int) ([]task.TimeEntry, error) {
+	var result []task.TimeEntry
+	running := 0
+	for i := 0; i < count; i++ {
+		startText := form.GetFormItem(i * 2).(*tview.InputField).GetText()
+		endText := form.GetFormItem(i*2 + 1).(*tview.InputField).GetText()
+		if startText == "" {
+			continue
+		}
+
+		start, err := time.ParseInLocation(entryTimeLayout, startText, time.Local)
+		if err != nil {
+			return nil, errors.New(i18n.T("entries.invalid_start", i+1, entryTimeLayout))
+		}
+
+		e := task.TimeEntry{Start: start}
+		if endText == "" {
+			running++
+		} else {
+			end, err := time.ParseInLocation(entryTimeLayout, endText, time.Local)
+			if err != nil {
+				return nil, errors.New(i18n.T("entries.invalid_end", i+1, entryTimeLayout))
+			}
+			if end.Before(start) {
+				return nil, errors.New(i18n.T("entries.end_before_start", i+1))
+			}
+			e.End = end
+		}
+		result = append(result, e)
+	}
+
+	if running > 1 {
+		return nil, errors.New(i18n.T("entries.one_running"))
+	}
+	return result, nil
+}
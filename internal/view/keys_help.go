@@ -0,0 +1,110 @@
+package view
+
+import (
+	"fmt"
+	"kongtools/internal/i18n"
+	"kongtools/internal/ui"
+	"log/slog"
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+func init() {
+	registerTool("keys", func(a *App, logger *slog.Logger) ui.Page {
+		return NewKeysHelp(logger, a.App)
+	})
+}
+
+// KeysHelp 按键帮助, 由按键配置生成
+type KeysHelp struct {
+	*tview.TextView
+
+	app    *ui.App
+	theme  *ui.Theme
+	logger *slog.Logger
+}
+
+// NewKeysHelp 新建
+func NewKeysHelp(logger *slog.Logger, app *ui.App) *KeysHelp {
+	h := &KeysHelp{
+		TextView: tview.NewTextView(),
+		app:      app,
+		logger:   logger.With("module", "view-keys-help"),
+	}
+
+	h.SetDynamicColors(true)
+	h.SetBorder(true).
+		SetTitle(i18n.T("keys.title")).
+		SetTitleAlign(tview.AlignCenter)
+	app.OnThemeChange(h.Info().Name, func(theme *ui.Theme) {
+		h.theme = theme
+		theme.ApplyTextView(h.TextView)
+		h.refresh()
+	})
+
+	return h
+}
+
+// Info 页面信息
+func (h *KeysHelp) Info() ui.PageInfo {
+	return ui.PageInfo{Name: "keys", Title: i18n.T("keys.title"), Description: i18n.T("keys.description"), Shortcut: 'k'}
+}
+
+// Primitive 页面内容
+func (h *KeysHelp) Primitive() tview.Primitive {
+	return h
+}
+
+// Init 注册打开帮助的全局按键
+func (h *KeysHelp) Init() error {
+	h.app.Keymap.Register(ui.ScopeGlobal, "help", i18n.T("action.global.help"), func() {
+		h.app.SwitchTo("keys")
+	}, "F1")
+	return nil
+}
+
+// OnFocus 按当前的按键配置生成
+func (h *KeysHelp) OnFocus() {
+	h.refresh()
+}
+
+// Shutdown 退出
+func (h *KeysHelp) Shutdown() {
+}
+
+func (h *KeysHelp) refresh() {
+	entries := h.app.Keymap.Help()
+
+	width := 0
+	for _, e := range entries {
+		if w := tview.TaggedStringWidth(tview.Escape(e.Keys)); w > width {
+			width = w
+		}
+	}
+
+	var b strings.Builder
+	scope := ""
+	for _, e := range entries {
+		if e.Scope != scope {
+			if scope != "" {
+				b.WriteString("\n")
+			}
+			scope = e.Scope
+			fmt.Fprintf(&b, "%s[::b]%s[-::-]\n", ui.Tag(h.theme.Title), tview.Escape(h.scopeTitle(scope)))
+		}
+		keys := tview.Escape(e.Keys)
+		padding := strings.Repeat(" ", width-tview.TaggedStringWidth(keys))
+		fmt.Fprintf(&b, "  %s%s[-]%s  %s\n", ui.Tag(h.theme.Accent), keys, padding, tview.Escape(e.Description))
+	}
+	h.SetText(b.String())
+	h.ScrollToBeginning()
+}
+
+// scopeTitle 作用域的标题, 页面的作用域用页面标题, 其他的来自文本目录
+func (h *KeysHelp) scopeTitle(scope string) string {
+	if p, ok := h.app.Registry.Page(scope); ok {
+		return p.Info().Title
+	}
+	return i18n.T("keys.scope." + scope)
+}
@@ -0,0 +1,155 @@
+package view
+
+import (
+	"errors"
+	"kongtools/internal/i18n"
+	"kongtools/internal/pkg/editor"
+	"kongtools/internal/ui"
+	"os"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// scopeNote 备注编辑器的按键作用域
+const scopeNote = "note"
+
+// noteEditor 任务备注的编辑器, 可以切换Markdown预览, 也可以在$EDITOR中编辑
+type noteEditor struct {
+	*tview.Flex
+	area    *tview.TextArea
+	preview *tview.TextView
+	help    *tview.TextView
+
+	original   string
+	previewing bool
+
+	app   *ui.App
+	theme *ui.Theme
+}
+
+// newNoteEditor 新建, title为任务标题
+func newNoteEditor(app *ui.App, title, note string, theme *ui.Theme) *noteEditor {
+	e := &noteEditor{
+		Flex:     tview.NewFlex(),
+		area:     tview.NewTextArea(),
+		preview:  tview.NewTextView(),
+		help:     tview.NewTextView(),
+		original: note,
+		app:      app,
+		theme:    theme,
+	}
+
+	theme.ApplyBox(e.Box)
+	theme.ApplyTextArea(e.area)
+	theme.ApplyTextView(e.preview)
+	theme.ApplyTextView(e.help)
+	e.help.SetTextColor(theme.Secondary)
+
+	e.area.SetText(note, false).
+		SetPlaceholder(i18n.T("note.placeholder"))
+	e.preview.SetDynamicColors(true).
+		SetWordWrap(true)
+
+	dispatch := func(event *tcell.EventKey) *tcell.EventKey {
+		return app.Keymap.Dispatch(scopeNote, event)
+	}
+	e.area.SetInputCapture(dispatch)
+	e.preview.SetInputCapture(dispatch)
+
+	e.SetDirection(tview.FlexRow).
+		SetBorder(true).
+		SetTitle(i18n.T("note.title", tview.Escape(title))).
+		SetTitleAlign(tview.AlignLeft)
+	e.AddItem(e.area, 0, 1, true).
+		AddItem(e.help, 1, 0, false)
+	e.updateHelp()
+	return e
+}
+
+// Text 编辑中的备注
+func (e *noteEditor) Text() string {
+	return e.area.GetText()
+}
+
+// Modified 是否有未保存的修改
+func (e *noteEditor) Modified() bool {
+	return e.Text() != e.original
+}
+
+// TogglePreview 在编辑和Markdown预览之间切换
+func (e *noteEditor) TogglePreview() {
+	e.previewing = !e.previewing
+	e.RemoveItem(e.area).RemoveItem(e.preview).RemoveItem(e.help)
+	focused := tview.Primitive(e.area)
+	if e.previewing {
+		e.preview.SetText(ui.RenderMarkdown(e.Text(), e.theme)).
+			ScrollToBeginning()
+		focused = e.preview
+	}
+	e.AddItem(focused, 0, 1, true).
+		AddItem(e.help, 1, 0, false)
+	e.updateHelp()
+	e.app.SetFocus(focused)
+}
+
+// OpenEditor 暂停界面, 在$VISUAL或$EDITOR中编辑备注, 退出编辑器后读回
+func (e *noteEditor) OpenEditor() error {
+	text, err := editExternal(e.app, e.Text())
+	if err != nil {
+		return err
+	}
+	e.area.SetText(text, false)
+	if e.previewing {
+		e.preview.SetText(ui.RenderMarkdown(text, e.theme))
+	}
+	return nil
+}
+
+// updateHelp 底部的按键提示
+func (e *noteEditor) updateHelp() {
+	var hints []string
+	for _, name := range []string{"save", "preview", "editor", "cancel"} {
+		if key := e.app.Keymap.Hint(scopeNote, name); key != "" {
+			hints = append(hints, key+" "+i18n.T("note.hint."+name))
+		}
+	}
+	mode := i18n.T("note.mode_edit")
+	if e.previewing {
+		mode = i18n.T("note.mode_preview")
+	}
+	e.help.SetText("[" + mode + "]  " + strings.Join(hints, "  "))
+}
+
+// editExternal 在外部编辑器中编辑text, 编辑器来自$VISUAL或$EDITOR, 都没有时用vi
+func editExternal(app *ui.App, text string) (string, error) {
+	file, err := os.CreateTemp("", "kongtools-note-*.md")
+	if err != nil {
+		return "", err
+	}
+	path := file.Name()
+	defer os.Remove(path)
+	if _, err := file.WriteString(text); err != nil {
+		file.Close()
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		return "", err
+	}
+
+	var runErr error
+	if !app.Suspend(func() { runErr = editor.Run(path) }) {
+		return "", errors.New("cannot suspend the application")
+	}
+	if runErr != nil {
+		return "", runErr
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	// 编辑器通常会在末尾加换行
+	return strings.TrimSuffix(string(data), "\n"), nil
+}
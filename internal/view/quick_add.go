@@ -0,0 +1,70 @@
+package view
+
+import (
+	"kongtools/internal/task"
+	"kongtools/internal/ui"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// quickAddText 高亮输入中识别出的标记, 到期日后面显示解析出的时间
+func quickAddText(text string, q task.QuickAdd, theme *ui.Theme) string {
+	var b strings.Builder
+	last := 0
+	for _, token := range q.Tokens {
+		b.WriteString(tview.Escape(text[last:token.Start]))
+		b.WriteString(ui.Tag(tokenColor(token.Kind, q.Priority, theme)))
+		b.WriteString(tview.Escape(text[token.Start:token.End]))
+		b.WriteString("[-]")
+		if token.Kind == task.TokenDue {
+			b.WriteString(ui.Tag(theme.Hint) + " (" + formatDue(*q.Due) + ")[-]")
+		}
+		last = token.End
+	}
+	b.WriteString(tview.Escape(text[last:]))
+	return b.String()
+}
+
+// tokenColor 标记的颜色
+func tokenColor(kind task.TokenKind, priority task.Priority, theme *ui.Theme) tcell.Color {
+	switch kind {
+	case task.TokenTag:
+		return theme.Secondary
+	case task.TokenPriority:
+		return priorityColor(priority, theme)
+	case task.TokenDue:
+		return theme.Hint
+	}
+	return theme.Accent
+}
+
+// priorityColor 优先级的颜色
+func priorityColor(priority task.Priority, theme *ui.Theme) tcell.Color {
+	switch priority {
+	case task.PriorityHigh:
+		return theme.Error
+	case task.PriorityMedium:
+		return theme.Accent
+	}
+	return theme.Secondary
+}
+
+// priorityText 列表中的优先级标记
+func priorityText(priority task.Priority, theme *ui.Theme) string {
+	if priority == task.PriorityNone {
+		return ""
+	}
+	return ui.Tag(priorityColor(priority, theme)) + strings.Repeat("!", int(priority)) + "[-] "
+}
+
+// formatDue 到期日的简短格式, 有时间时显示时间
+func formatDue(due time.Time) string {
+	due = due.Local()
+	if due.Hour() != 0 || due.Minute() != 0 {
+		return due.Format("01-02 15:04")
+	}
+	return due.Format("01-02")
+}
@@ -0,0 +1,100 @@
+package view
+
+import (
+	"errors"
+	"kongtools/internal/i18n"
+	"kongtools/internal/ui"
+	"log/slog"
+	"reflect"
+	"strings"
+)
+
+// Reload 在UI goroutine中应用修改后的配置并等待完成, 不能在UI goroutine中调用.
+// 不能应用时恢复原来的配置并返回错误
+func (a *App) Reload(cfg Config) error {
+	done := make(chan error, 1)
+	a.Background.Post(func() {
+		done <- a.applyConfig(cfg)
+	})
+	select {
+	case err := <-done:
+		return err
+	case <-a.Background.Done():
+		return errors.New("app stopped")
+	}
+}
+
+// ReloadError 提示修改后的配置无效, 继续使用原来的配置. 可以在任意goroutine中调用
+func (a *App) ReloadError(err error) {
+	a.logger.Warn("reload config error", slog.String("error", err.Error()))
+	// 通知只显示一行, 多个问题用分号分隔
+	lines := strings.Split(err.Error(), "\n")
+	message := lines[0]
+	for i, line := range lines[1:] {
+		sep := "; "
+		if i == 0 {
+			sep = " "
+		}
+		message += sep + strings.TrimSpace(line)
+	}
+	a.Notify(ui.SeverityError, i18n.T("app.config_invalid", message))
+}
+
+// applyConfig 应用新配置中的主题、任务文件、启用的工具和按键, 其他修改在重启后生效.
+// 全部成功后才使用新配置, 有错误时已经应用的部分恢复为原来的配置
+func (a *App) applyConfig(cfg Config) error {
+	old := a.cfg
+	a.logger.Debug("apply config ...")
+
+	steps := []struct {
+		changed bool
+		apply   func(cfg Config) error
+	}{
+		{!reflect.DeepEqual(old.Theme, cfg.Theme), func(cfg Config) error {
+			return a.InitTheme(cfg.Theme)
+		}},
+		{old.TasksSavePath != cfg.TasksSavePath, func(cfg Config) error {
+			return a.Store.SetPath(cfg.TasksSavePath)
+		}},
+		// 新建的页面要应用按键配置, 一起重新应用
+		{!reflect.DeepEqual(old.Tools, cfg.Tools), func(cfg Config) error {
+			pages, err := a.toolPages(cfg.Tools)
+			if err != nil {
+				return err
+			}
+			return a.UpdatePages(pages, cfg.Keys)
+		}},
+		{reflect.DeepEqual(old.Tools, cfg.Tools) && !reflect.DeepEqual(old.Keys, cfg.Keys), func(cfg Config) error {
+			return a.ReloadKeys(cfg.Keys)
+		}},
+	}
+	for i, step := range steps {
+		if !step.changed {
+			continue
+		}
+		if err := step.apply(cfg); err != nil {
+			a.logger.Error("apply config error", slog.String("error", err.Error()))
+			// 出错的一步可能只应用了一部分, 也要恢复
+			for _, done := range steps[:i+1] {
+				if !done.changed {
+					continue
+				}
+				if err := done.apply(old); err != nil {
+					a.logger.Error("restore config error", slog.String("error", err.Error()))
+				}
+			}
+			return err
+		}
+	}
+	a.cfg = cfg
+
+	// 其余配置只在启动时读取
+	rest := old
+	rest.Theme, rest.TasksSavePath, rest.Tools, rest.Keys = cfg.Theme, cfg.TasksSavePath, cfg.Tools, cfg.Keys
+	if !reflect.DeepEqual(rest, cfg) {
+		a.Notify(ui.SeverityWarning, i18n.T("app.config_restart"))
+		return nil
+	}
+	a.Notify(ui.SeveritySuccess, i18n.T("app.config_reloaded"))
+	return nil
+}
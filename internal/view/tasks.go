@@ -0,0 +1,34 @@
+package view
+
+import (
+	"kongtools/internal/i18n"
+	"kongtools/internal/task"
+	"kongtools/internal/ui"
+	"log/slog"
+)
+
+type Task = task.Task
+
+// recordTask 记录任务变更, 失败只记日志
+func recordTask(logger *slog.Logger, store *task.Store, action string, item Task) {
+	if err := store.Record(action, item); err != nil {
+		logger.Error("Failed to record task event", slog.String("action", action), slog.String("error", err.Error()))
+	}
+}
+
+// scheduleSave 延迟保存任务, 结果通过通知显示, 失败时弹出错误
+func scheduleSave(logger *slog.Logger, app *ui.App, store *task.Store) {
+	store.ScheduleSave(func(err error) {
+		if err != nil {
+			logger.Error("Failed to save tasks", slog.String("error", err.Error()))
+			app.Notify(ui.SeverityError, i18n.T("todo.save_failed")+".")
+			app.Background.Post(func() {
+				app.ShowError(i18n.T("todo.save_failed"), err)
+			})
+			return
+		}
+		logger.Info("Tasks saved", slog.String("savePath", store.Path()))
+
+		app.Notify(ui.SeveritySuccess, i18n.T("todo.saved", store.Path()))
+	})
+}
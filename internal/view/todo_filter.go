@@ -0,0 +1,40 @@
+package view
+
+import (
+	"kongtools/internal/i18n"
+	"time"
+)
+
+// dueLayout 到期时间的输入格式
+const dueLayout = "2006-01-02 15:04"
+
+// parseDue 解析到期时间, 支持只输入日期
+func parseDue(text string) (time.Time, error) {
+	if due, err := time.ParseInLocation(dueLayout, text, time.Local); err == nil {
+		return due, nil
+	}
+	return time.ParseInLocation(time.DateOnly, text, time.Local)
+}
+
+// taskFilter 任务过滤条件
+type taskFilter struct {
+	Due time.Time // 零值表示不限
+}
+
+func (f taskFilter) empty() bool {
+	return f.Due.IsZero()
+}
+
+func (f taskFilter) match(item Task) bool {
+	if !f.Due.IsZero() && !item.DueOn(f.Due) {
+		return false
+	}
+	return true
+}
+
+func (f taskFilter) String() string {
+	if f.Due.IsZero() {
+		return ""
+	}
+	return i18n.T("todo.filter_due", f.Due.Format(time.DateOnly))
+}
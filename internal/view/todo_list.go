@@ -2,78 +2,236 @@ package view
 
 import (
 	"encoding/json"
+	"kongtools/internal/i18n"
+	"kongtools/internal/task"
+	"kongtools/internal/ui"
 	"log/slog"
-	"os"
-	"path/filepath"
-	"sync"
+	"strings"
 	"time"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
 
-type Task struct {
-	Title     string
-	Completed bool
+func init() {
+	registerTool("todo-list", func(a *App, logger *slog.Logger) ui.Page {
+		return NewTodoList(logger, a.App, a.Store, a.workflow, a.cfg.Todo)
+	})
+}
+
+// TodoConfig 待办列表配置
+type TodoConfig struct {
+	TitleLimit int // 标题的最大显示宽度, 中文占两列
 }
 
+// defaultTitleLimit 没有配置时标题的最大显示宽度
+const defaultTitleLimit = 80
+
 type TodoList struct {
 	// ui
 	*tview.Flex
-	input *tview.InputField
-	hint  *tview.TextView
-	tasks *tview.List
+	input   *tview.InputField
+	preview *tview.TextView // 高亮快速添加语法, 有识别出的标记时显示
+	tasks   *tview.List
+	body    *tview.Pages
 
 	// data
-	savePath  string
-	taskItems []Task
+	store     *task.Store
+	workflow  task.Workflow
+	cfg       TodoConfig
+	filter    taskFilter
+	selection taskSelection
+	visible   []int // 列表中每一行对应的任务序号
 
 	// control
 	editMode  bool
+	editDue   bool
 	editIndex int
-	hintTimer *time.Timer
-	saveTimer *time.Timer
-	mutex     *sync.Mutex
+	note      *noteEditor // 打开中的备注编辑器
+	noteIndex int
+	noteTask  Task // 打开编辑器时的任务, 保存前检查是否被修改
 
 	// global
+	app    *ui.App
+	theme  *ui.Theme
 	logger *slog.Logger
 }
 
-func NewTodoList(logger *slog.Logger, savePath string) *TodoList {
+func NewTodoList(logger *slog.Logger, app *ui.App, store *task.Store, workflow task.Workflow, cfg TodoConfig) *TodoList {
+	if cfg.TitleLimit <= 0 {
+		cfg.TitleLimit = defaultTitleLimit
+	}
+
 	todoList := &TodoList{
 		Flex:      tview.NewFlex(),
 		input:     tview.NewInputField(),
-		hint:      tview.NewTextView(),
+		preview:   tview.NewTextView(),
 		tasks:     tview.NewList(),
-		savePath:  savePath,
-		taskItems: []Task{},
+		body:      tview.NewPages(),
+		store:     store,
+		workflow:  workflow,
+		cfg:       cfg,
 		editMode:  false,
 		editIndex: -1,
-		hintTimer: nil,
-		saveTimer: nil,
-		mutex:     &sync.Mutex{},
+		selection: newTaskSelection(),
+		app:       app,
 		logger:    logger.With("module", "view-todo-list"),
 	}
 
-	err := todoList.loadTasks(savePath)
-	if err != nil {
-		todoList.logger.Error("load tasks error", slog.String("error", err.Error()))
-	}
-
+	app.OnThemeChange(todoList.Info().Name, todoList.applyTheme)
 	todoList.initTasks()
 	todoList.updateInputLabel()
 	todoList.configureHandlers()
 	todoList.setupLayout()
 
+	// 其他视图修改任务后刷新, 任务序号可能改变, 清除选择
+	store.Subscribe("todo-list", func() {
+		todoList.selection = newTaskSelection()
+		todoList.updateTasksDisplay(0)
+		todoList.updateTitle()
+	})
+
 	return todoList
 }
 
+// Info 页面信息
+func (t *TodoList) Info() ui.PageInfo {
+	return ui.PageInfo{Name: "todo-list", Title: i18n.T("todo.name"), Description: i18n.T("todo.description"), Shortcut: 't'}
+}
+
+// Primitive 页面内容
+func (t *TodoList) Primitive() tview.Primitive {
+	return t
+}
+
+// Init 注册按键动作
+func (t *TodoList) Init() error {
+	keymap := t.app.Keymap
+	keymap.Register("todo-list", "complete", i18n.T("action.todo-list.complete"), t.CompleteTask, "Space")
+	keymap.Register("todo-list", "timer", i18n.T("action.todo-list.timer"), t.ToggleTimer, "s")
+	keymap.Register("todo-list", "entries", i18n.T("action.todo-list.entries"), t.EditEntries, "T")
+	keymap.Register("todo-list", "due", i18n.T("action.todo-list.due"), t.EditDue, "d")
+	keymap.Register("todo-list", "delete", i18n.T("action.todo-list.delete"), t.DeleteTask, "Delete")
+	keymap.Register("todo-list", "edit", i18n.T("action.todo-list.edit"), func() {
+		if t.editMode {
+			t.SaveEdit()
+		} else {
+			t.EditTask()
+		}
+	}, "Enter")
+	keymap.Register("todo-list", "cancel", i18n.T("action.todo-list.cancel"), func() {
+		if t.editMode {
+			t.CancelEdit()
+		} else if t.ClearSelection() {
+			// 有选择时先取消选择
+		} else if !t.filter.empty() {
+			// 记录到导航历史, 可以后退到过滤的列表
+			t.app.Navigate("todo-list", nil)
+		}
+	}, "Esc")
+	keymap.Register("todo-list", "input", i18n.T("action.todo-list.input"), func() {
+		t.app.SetFocus(t.input)
+	}, "Tab")
+	keymap.Register("todo-list", "note", i18n.T("action.todo-list.note"), t.EditNote, "n")
+	keymap.Register("todo-list", "mark", i18n.T("action.todo-list.mark"), t.ToggleMark, "m")
+	keymap.Register("todo-list", "range", i18n.T("action.todo-list.range"), t.ToggleRange, "v")
+	keymap.Register("todo-list", "select-all", i18n.T("action.todo-list.select-all"), t.SelectAll, "Ctrl+A")
+	keymap.Register("todo-list", "tag", i18n.T("action.todo-list.tag"), t.TagSelected, "#")
+	keymap.Register("todo-list", "move", i18n.T("action.todo-list.move"), t.MoveSelected, ">")
+	keymap.Register("todo-list", "undo", i18n.T("action.todo-list.undo"), t.Undo, "u")
+
+	keymap.Register(scopeNote, "save", i18n.T("action.note.save"), t.saveNote, "Ctrl+S")
+	keymap.Register(scopeNote, "preview", i18n.T("action.note.preview"), func() {
+		if t.note != nil {
+			t.note.TogglePreview()
+		}
+	}, "Ctrl+R")
+	keymap.Register(scopeNote, "editor", i18n.T("action.note.editor"), func() {
+		if t.note == nil {
+			return
+		}
+		if err := t.note.OpenEditor(); err != nil {
+			t.app.ShowError(i18n.T("note.editor_failed"), err)
+		}
+	}, "Ctrl+O")
+	keymap.Register(scopeNote, "cancel", i18n.T("action.note.cancel"), t.cancelNote, "Esc")
+	return nil
+}
+
+// SetParams 页面参数: due 只显示该日期到期的任务, 格式为 2006-01-02
+func (t *TodoList) SetParams(params map[string]string) {
+	text, ok := params["due"]
+	if !ok {
+		t.ClearFilter()
+		return
+	}
+	day, err := time.ParseInLocation(time.DateOnly, text, time.Local)
+	if err != nil {
+		t.app.Notify(ui.SeverityWarning, i18n.T("todo.invalid_filter", text))
+		t.ClearFilter()
+		return
+	}
+	t.SetDueFilter(day)
+}
+
+// OnFocus 切换到该页面
+func (t *TodoList) OnFocus() {
+}
+
+// todoListState 会话状态
+type todoListState struct {
+	Current int    `json:"current"`
+	Offset  int    `json:"offset"`
+	Input   string `json:"input,omitempty"` // 未提交的新任务
+}
+
+// SaveState 保存选中的任务, 滚动位置和输入. 过滤条件保存在页面参数中
+func (t *TodoList) SaveState() any {
+	state := todoListState{Current: t.tasks.GetCurrentItem()}
+	state.Offset, _ = t.tasks.GetOffset()
+	if !t.editMode {
+		state.Input = t.input.GetText()
+	}
+	return state
+}
+
+// RestoreState 恢复状态
+func (t *TodoList) RestoreState(data []byte) error {
+	var state todoListState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+	if state.Current < t.tasks.GetItemCount() {
+		t.tasks.SetCurrentItem(state.Current)
+	}
+	t.tasks.SetOffset(state.Offset, 0)
+	t.input.SetText(state.Input)
+	return nil
+}
+
+// Shutdown 退出
+func (t *TodoList) Shutdown() {
+}
+
+func (t *TodoList) applyTheme(theme *ui.Theme) {
+	t.theme = theme
+	theme.ApplyBox(t.Box)
+	theme.ApplyInputField(t.input)
+	theme.ApplyTextView(t.preview)
+	theme.ApplyList(t.tasks)
+	theme.ApplyBox(t.body.Box)
+	if t.tasks.GetItemCount() > 0 {
+		t.updateInputLabel()
+		t.updateTasksDisplay(0)
+	}
+}
+
 func (t *TodoList) initTasks() {
 	t.tasks.Clear()
-	t.logger.Debug("init tasks", slog.Int("count", len(t.taskItems)))
+	t.logger.Debug("init tasks", slog.Int("count", t.store.Len()))
 	t.tasks.ShowSecondaryText(false)
 
-	if len(t.taskItems) == 0 {
+	if t.store.Len() == 0 {
 		t.logger.Debug("No tasks found, adding help messages")
 		t.addHelpMessages()
 		return
@@ -82,29 +240,63 @@ func (t *TodoList) initTasks() {
 }
 
 func (t *TodoList) displayTask(task Task) {
-	title := task.Title
+	t.tasks.AddItem(t.rowText(t.tasks.GetItemCount(), task), "", 0, nil)
+}
+
+// taskText 任务在列表中的显示文本
+func taskText(item Task, theme *ui.Theme) string {
+	// 标题可能包含 [red] 之类的文本, 转义后原样显示
+	title := tview.Escape(item.Title)
 
-	if task.Completed {
-		title = "[gray]" + tview.Escape("[x]") + title + "[-]"
+	if item.Completed {
+		title = ui.Tag(theme.Completed) + tview.Escape("[x]") + title + "[-]"
 	} else {
-		title = "[white]" + tview.Escape("[ ]") + title + "[-]"
+		title = ui.Tag(theme.Text) + tview.Escape("[ ]") + title + "[-]"
+	}
+
+	if !item.Completed {
+		title = priorityText(item.Priority, theme) + title
 	}
 
-	t.tasks.AddItem(title, "", 0, nil)
+	if len(item.Tags) > 0 {
+		title += " " + ui.Tag(theme.Secondary) + tview.Escape("#"+strings.Join(item.Tags, " #")) + "[-]"
+	}
+
+	if item.Due != nil {
+		due := formatDue(*item.Due)
+		if item.Overdue(time.Now()) {
+			title += " " + ui.Tag(theme.Error) + "📅 " + due + "[-]"
+		} else {
+			title += " " + ui.Tag(theme.Hint) + "📅 " + due + "[-]"
+		}
+	}
+
+	if item.Recurrence != "" {
+		title += " " + ui.Tag(theme.Accent) + "↻ " + tview.Escape(item.Recurrence) + "[-]"
+	}
+
+	if item.Note != "" {
+		title += " " + ui.Tag(theme.Secondary) + "✎[-]"
+	}
+
+	if len(item.Entries) > 0 {
+		d := item.TotalDuration(time.Now())
+		if item.Running() {
+			title += " " + ui.Tag(theme.Accent) + "⏱ " + task.FormatDuration(d) + "[-]"
+		} else {
+			title += " " + ui.Tag(theme.Secondary) + "(" + task.FormatDuration(d) + ")[-]"
+		}
+	}
+
+	return title
 }
 
 func (t *TodoList) displayTaskUpdate(index int, task Task) {
-	if index >= len(t.taskItems) {
+	if index >= len(t.visible) {
 		return
 	}
 
-	title := task.Title
-
-	if task.Completed {
-		title = "[gray]" + tview.Escape("[x]") + title + "[-]"
-	} else {
-		title = "[white]" + tview.Escape("[ ]") + title + "[-]"
-	}
+	title := t.rowText(index, task)
 
 	if index < t.tasks.GetItemCount() {
 		t.tasks.SetItemText(index, title, "")
@@ -113,9 +305,18 @@ func (t *TodoList) displayTaskUpdate(index int, task Task) {
 	}
 }
 
+// updateTasksDisplay 从列表第index行开始刷新
 func (t *TodoList) updateTasksDisplay(index int) {
-	if index >= len(t.taskItems) {
-		index = len(t.taskItems) - 1
+	items := t.store.Tasks()
+	t.visible = t.visible[:0]
+	for i, item := range items {
+		if t.filter.match(item) {
+			t.visible = append(t.visible, i)
+		}
+	}
+
+	if index >= len(t.visible) {
+		index = len(t.visible) - 1
 	}
 	// 删除index之后
 	for i := t.tasks.GetItemCount() - 1; i > index; i-- {
@@ -125,171 +326,502 @@ func (t *TodoList) updateTasksDisplay(index int) {
 	if index < 0 {
 		return
 	}
-	t.displayTaskUpdate(index, t.taskItems[index])
+	t.displayTaskUpdate(index, items[t.visible[index]])
 
-	for i, task := range t.taskItems {
+	for i, taskIndex := range t.visible {
 		if i <= index {
 			continue
 		}
 
-		t.displayTask(task)
+		t.displayTask(items[taskIndex])
 	}
 }
 
-func (t *TodoList) updateInputLabel() {
-	label := "New To-Do: "
-	if t.editMode {
-		label = "Edit To-Do: "
+// currentIndex 当前选中的任务序号
+func (t *TodoList) currentIndex() (int, bool) {
+	current := t.tasks.GetCurrentItem()
+	if current < 0 || current >= len(t.visible) {
+		return -1, false
 	}
-	t.input.SetLabel(label).
-		SetLabelColor(tcell.ColorYellow).
-		SetLabelWidth(len(label))
+	return t.visible[current], true
+}
+
+// SetDueFilter 只显示day当天到期的任务
+func (t *TodoList) SetDueFilter(day time.Time) {
+	t.CancelEdit()
+	t.selection = newTaskSelection()
+	t.filter.Due = day
+	t.updateTitle()
+	t.updateTasksDisplay(0)
+	t.tasks.SetCurrentItem(0)
+	t.logger.Debug("Task filter set", slog.String("filter", t.filter.String()))
 }
 
-func (t *TodoList) updateHint(message string) {
-	if t.hintTimer != nil {
-		t.hintTimer.Stop()
+// ClearFilter 清除过滤
+func (t *TodoList) ClearFilter() {
+	if t.filter.empty() {
+		return
 	}
+	t.filter = taskFilter{}
+	t.selection = newTaskSelection()
+	t.updateTitle()
+	t.updateTasksDisplay(0)
+	t.logger.Debug("Task filter cleared")
+}
 
-	t.hint.SetText(message).
-		SetTextColor(tcell.ColorRed)
+func (t *TodoList) updateTitle() {
+	title := i18n.T("todo.title")
+	if !t.filter.empty() {
+		title = i18n.T("todo.title_filtered", t.filter.String())
+	}
+	if n := len(t.selected()); n > 0 {
+		title += " " + i18n.T("todo.title_selected", n)
+	}
+	t.SetTitle(title)
+}
 
-	if message != "" {
-		t.hintTimer = time.AfterFunc(3*time.Second, func() {
-			t.hint.SetText("")
-		})
+func (t *TodoList) updateInputLabel() {
+	label := i18n.T("todo.label_new")
+	if t.editDue {
+		label = i18n.T("todo.label_due", dueLayout)
+	} else if t.editMode {
+		label = i18n.T("todo.label_edit")
 	}
+	t.input.SetLabel(label).
+		SetLabelColor(t.theme.Accent).
+		SetLabelWidth(tview.TaggedStringWidth(label))
+	t.updatePreview(t.input.GetText())
 }
 
+// updatePreview 新建任务时高亮识别出的快速添加标记, 编辑时不解析
+func (t *TodoList) updatePreview(text string) {
+	var q task.QuickAdd
+	if !t.editMode {
+		q = task.ParseQuickAdd(text, time.Now())
+	}
+	if len(q.Tokens) == 0 {
+		t.preview.Clear()
+		t.ResizeItem(t.preview, 0, 0)
+		return
+	}
+	t.preview.SetText(quickAddText(text, q, t.theme))
+	t.ResizeItem(t.preview, 1, 0)
+}
+
+// helpMessage 第一次使用时添加的示例任务的键
 var helpMessage = []string{
-	"💡Write your first to-do task in the input field above.",
-	"👏Press Enter to add the task to the list.",
-	"📝Select a task and press Enter to edit it.",
-	"🤷Press Esc to cancel editing a task.",
-	"🥷Press Delete to remove a selected task.",
-	"✅Press Space to mark a task as completed.",
+	"todo.help.write",
+	"todo.help.add",
+	"todo.help.edit",
+	"todo.help.cancel",
+	"todo.help.delete",
+	"todo.help.complete",
+	"todo.help.timer",
+	"todo.help.entries",
+	"todo.help.due",
+	"todo.help.quickadd",
+	"todo.help.select",
 }
 
 func (t *TodoList) addHelpMessages() {
-	for _, msg := range helpMessage {
-		newTask := Task{
-			Title:     msg,
-			Completed: false,
+	t.store.Update("todo-list", func(tasks []Task) []Task {
+		for _, msg := range helpMessage {
+			newTask := Task{
+				Title:     i18n.T(msg),
+				Completed: false,
+			}
+			tasks = append(tasks, newTask)
 		}
-		t.taskItems = append(t.taskItems, newTask)
-	}
+		return tasks
+	})
 	t.updateTasksDisplay(0)
 }
 
 func (t *TodoList) AddTask() {
-	task := t.input.GetText()
-	if task != "" {
+	text := t.input.GetText()
+	if text != "" {
+		now := time.Now()
+		q := task.ParseQuickAdd(text, now)
+		if q.Title == "" {
+			t.app.Notify(ui.SeverityWarning, i18n.T("todo.empty_title"))
+			return
+		}
+		if task.TitleWidth(q.Title) > t.cfg.TitleLimit {
+			t.app.Notify(ui.SeverityWarning, i18n.T("todo.too_long", t.cfg.TitleLimit))
+			return
+		}
 		newTask := Task{
-			Title:     task,
 			Completed: false,
+			CreatedAt: now,
 		}
-		t.taskItems = append(t.taskItems, newTask)
-		t.updateTasksDisplay(len(t.taskItems) - 1)
+		q.Apply(&newTask)
+		t.workflow.SetCompleted(&newTask, false)
+		t.store.Checkpoint(i18n.T("todo.bulk.add"))
+		if newTask.Due == nil && !t.filter.Due.IsZero() {
+			// 按日期过滤时, 新任务默认在当天到期
+			due := t.filter.Due
+			newTask.Due = &due
+		}
+		t.store.Update("todo-list", func(tasks []Task) []Task {
+			return append(tasks, newTask)
+		})
+		t.updateTasksDisplay(len(t.visible))
 		t.input.SetText("")
-		t.logger.Debug("Task added", slog.String("task", task))
+		t.logger.Debug("Task added", slog.String("task", newTask.Title))
+		recordTask(t.logger, t.store, task.ActionCreated, newTask)
 
-		t.scheduleSave(t.savePath)
+		t.scheduleSave()
 	}
 }
 
 func (t *TodoList) DeleteTask() {
 	if t.editMode {
 		t.logger.Debug("Task edit mode, can't delete")
-		t.updateHint("Cannot delete while editing a task.")
+		t.app.Notify(ui.SeverityWarning, i18n.T("todo.delete_editing"))
 		return
 	}
 	if t.tasks.GetItemCount() == 0 {
 		t.logger.Debug("Task list is empty, can't delete")
-		t.updateHint("Task list is empty. Add a task first.")
+		t.app.Notify(ui.SeverityWarning, i18n.T("todo.empty"))
+		return
+	}
+	if t.selection.active() {
+		t.deleteSelected()
 		return
 	}
 
-	index := t.tasks.GetCurrentItem()
-	if index >= len(t.taskItems) {
+	index, _ := t.currentIndex()
+	item, ok := t.store.Get(index)
+	if !ok {
 		return
 	}
 
-	task := t.taskItems[index].Title
-	t.taskItems = append(t.taskItems[:index], t.taskItems[index+1:]...)
-	t.updateTasksDisplay(index)
-	t.logger.Debug("Task deleted", slog.String("task", task))
+	t.app.Confirm(i18n.T("todo.delete_title"), i18n.T("todo.delete_message", item.Title), i18n.T("todo.delete_ok"), func() {
+		t.deleteTask(index, item)
+	})
+}
+
+// deleteTask 确认后删除, 期间任务被其他视图修改时放弃
+func (t *TodoList) deleteTask(index int, item Task) {
+	if current, ok := t.store.Get(index); !ok || current.Title != item.Title || !current.CreatedAt.Equal(item.CreatedAt) {
+		t.app.Notify(ui.SeverityWarning, i18n.T("todo.changed"))
+		return
+	}
+
+	t.store.Checkpoint(i18n.T("todo.bulk.delete"))
+	t.store.Update("todo-list", func(tasks []Task) []Task {
+		return append(tasks[:index], tasks[index+1:]...)
+	})
+	t.updateTasksDisplay(t.tasks.GetCurrentItem())
+	t.logger.Debug("Task deleted", slog.String("task", item.Title))
+	recordTask(t.logger, t.store, task.ActionDeleted, item)
 
-	t.scheduleSave(t.savePath)
+	t.scheduleSave()
 }
 
 func (t *TodoList) EditTask() {
 	if t.tasks.GetItemCount() > 0 {
-		index := t.tasks.GetCurrentItem()
-		if index >= len(t.taskItems) {
+		index, _ := t.currentIndex()
+		item, ok := t.store.Get(index)
+		if !ok {
 			return
 		}
 
-		task := t.taskItems[index].Title
-		t.input.SetText(task)
+		t.input.SetText(item.Title)
 		t.editMode = true
 		t.editIndex = index
 		t.updateInputLabel()
-		t.logger.Debug("Task edit", slog.String("task", task))
+		t.logger.Debug("Task edit", slog.String("task", item.Title))
 	}
 }
 
+func (t *TodoList) EditDue() {
+	index, _ := t.currentIndex()
+	item, ok := t.store.Get(index)
+	if !ok {
+		return
+	}
+
+	due := ""
+	if item.Due != nil {
+		due = item.Due.Local().Format(dueLayout)
+	}
+	t.input.SetText(due)
+	t.editMode = true
+	t.editDue = true
+	t.editIndex = index
+	t.updateInputLabel()
+	t.app.SetFocus(t.input)
+	t.logger.Debug("Task due edit", slog.String("task", item.Title))
+}
+
 func (t *TodoList) SaveEdit() {
+	if t.editDue {
+		t.saveDue()
+		return
+	}
 	if t.editMode {
-		task := t.input.GetText()
+		title := t.input.GetText()
 		index := t.editIndex
-		if task != "" && index >= 0 {
-			t.taskItems[index].Title = task
+		if title != "" && index >= 0 && index < t.store.Len() {
+			t.store.Checkpoint(i18n.T("todo.bulk.edit"))
+			t.store.Update("todo-list", func(tasks []Task) []Task {
+				tasks[index].Title = title
+				return tasks
+			})
 			t.input.SetText("")
 			t.editMode = false
 			t.editIndex = -1
 			t.updateInputLabel()
-			t.updateTasksDisplay(index)
-			t.logger.Debug("Task edited", slog.String("task", task))
+			t.updateTasksDisplay(t.tasks.GetCurrentItem())
+			t.logger.Debug("Task edited", slog.String("task", title))
 
-			t.scheduleSave(t.savePath)
+			t.scheduleSave()
 		}
 	}
 }
 
+// saveDue 保存到期时间, 输入为空表示清除
+func (t *TodoList) saveDue() {
+	index := t.editIndex
+	if index < 0 || index >= t.store.Len() {
+		return
+	}
+
+	var due *time.Time
+	if text := t.input.GetText(); text != "" {
+		d, err := parseDue(text)
+		if err != nil {
+			t.app.Notify(ui.SeverityWarning, i18n.T("todo.invalid_due", dueLayout, time.DateOnly))
+			return
+		}
+		due = &d
+	}
+
+	t.store.Checkpoint(i18n.T("todo.bulk.due"))
+	t.store.Update("todo-list", func(tasks []Task) []Task {
+		tasks[index].Due = due
+		return tasks
+	})
+	t.input.SetText("")
+	t.editMode = false
+	t.editDue = false
+	t.editIndex = -1
+	t.updateInputLabel()
+	t.updateTasksDisplay(0)
+	t.app.SetFocus(t.tasks)
+	t.logger.Debug("Task due edited", slog.Int("task", index))
+
+	t.scheduleSave()
+}
+
 func (t *TodoList) CancelEdit() {
 	if t.editMode {
 		t.input.SetText("")
 		t.editMode = false
+		t.editDue = false
 		t.editIndex = -1
 		t.updateInputLabel()
+		t.app.SetFocus(t.tasks)
 		t.logger.Debug("Task edit canceled")
 	}
 }
 
 func (t *TodoList) CompleteTask() {
-	index := t.tasks.GetCurrentItem()
-	if index >= len(t.taskItems) {
+	if t.selection.active() {
+		t.completeSelected()
+		return
+	}
+	index, _ := t.currentIndex()
+	item, ok := t.store.Get(index)
+	if !ok {
+		return
+	}
+
+	t.workflow.SetCompleted(&item, !item.Completed)
+	if item.Completed {
+		t.store.Checkpoint(i18n.T("todo.bulk.complete"))
+	} else {
+		t.store.Checkpoint(i18n.T("todo.bulk.reopen"))
+	}
+	t.store.Update("todo-list", func(tasks []Task) []Task {
+		tasks[index] = item
+		return tasks
+	})
+	t.updateTasksDisplay(t.tasks.GetCurrentItem())
+	t.logger.Debug("Task completion toggled", slog.String("task", item.Title), slog.Bool("completed", item.Completed))
+	if item.Completed {
+		recordTask(t.logger, t.store, task.ActionCompleted, item)
+	} else {
+		recordTask(t.logger, t.store, task.ActionReopened, item)
+	}
+
+	t.scheduleSave()
+}
+
+func (t *TodoList) ToggleTimer() {
+	index, _ := t.currentIndex()
+	item, ok := t.store.Get(index)
+	if !ok {
+		return
+	}
+
+	if item.Running() {
+		t.store.Checkpoint(i18n.T("todo.bulk.timer_stop"))
+	} else {
+		t.store.Checkpoint(i18n.T("todo.bulk.timer_start"))
+	}
+	now := time.Now()
+	t.store.Update("todo-list", func(tasks []Task) []Task {
+		if tasks[index].Running() {
+			tasks[index].StopTimer(now)
+		} else {
+			task.StartTimer(tasks, index, now)
+		}
+		return tasks
+	})
+	t.logger.Debug("Task timer toggled", slog.String("task", item.Title), slog.Bool("running", !item.Running()))
+	// 其他任务的计时可能被停止, 全部刷新
+	t.updateTasksDisplay(0)
+
+	t.scheduleSave()
+}
+
+func (t *TodoList) EditEntries() {
+	if t.editMode {
+		t.app.Notify(ui.SeverityWarning, i18n.T("todo.entries_editing"))
+		return
+	}
+	index, _ := t.currentIndex()
+	item, ok := t.store.Get(index)
+	if !ok {
+		return
+	}
+
+	form := newEntriesForm(item, func(entries []task.TimeEntry) {
+		if removed := len(item.Entries) - len(entries); removed > 0 {
+			t.app.Confirm(i18n.T("entries.remove_title"), i18n.T("entries.remove_message", removed, item.Title), i18n.T("entries.remove_ok"), func() {
+				t.saveEntries(index, item, entries)
+			})
+			return
+		}
+		t.saveEntries(index, item, entries)
+	}, t.closeEntries, func(err error) {
+		t.app.ShowError(i18n.T("entries.invalid"), err)
+	}, t.theme)
+
+	t.body.AddAndSwitchToPage("entries", form, true)
+}
+
+// saveEntries 保存计时记录并关闭编辑
+func (t *TodoList) saveEntries(index int, item Task, entries []task.TimeEntry) {
+	t.store.Checkpoint(i18n.T("todo.bulk.entries"))
+	t.store.Update("todo-list", func(tasks []Task) []Task {
+		tasks[index].Entries = entries
+		if tasks[index].Running() {
+			// 同一时间只允许一个计时
+			task.StartTimer(tasks, index, time.Now())
+		}
+		return tasks
+	})
+	t.closeEntries()
+	t.updateTasksDisplay(0)
+	t.logger.Debug("Task time entries edited", slog.String("task", item.Title), slog.Int("count", len(entries)))
+
+	t.scheduleSave()
+}
+
+func (t *TodoList) closeEntries() {
+	t.body.SwitchToPage("tasks")
+	t.body.RemovePage("entries")
+}
+
+// EditNote 打开当前任务的备注编辑器
+func (t *TodoList) EditNote() {
+	if t.editMode {
+		t.app.Notify(ui.SeverityWarning, i18n.T("note.editing"))
+		return
+	}
+	index, _ := t.currentIndex()
+	item, ok := t.store.Get(index)
+	if !ok {
+		return
+	}
+
+	t.noteIndex, t.noteTask = index, item
+	t.note = newNoteEditor(t.app, item.Title, item.Note, t.theme)
+	t.body.AddAndSwitchToPage("note", t.note, true)
+	t.app.SetFocus(t.note)
+}
+
+// saveNote 保存备注并关闭编辑器, 期间任务被其他视图修改时放弃
+func (t *TodoList) saveNote() {
+	if t.note == nil {
+		return
+	}
+	index, note := t.noteIndex, t.note.Text()
+	item, ok := t.store.Get(index)
+	if !ok || item.Note != t.noteTask.Note || item.Title != t.noteTask.Title || !item.CreatedAt.Equal(t.noteTask.CreatedAt) {
+		t.app.Notify(ui.SeverityWarning, i18n.T("todo.changed"))
 		return
 	}
 
-	t.taskItems[index].Completed = !t.taskItems[index].Completed
-	t.updateTasksDisplay(index)
-	t.logger.Debug("Task completion toggled", slog.String("task", t.taskItems[index].Title), slog.Bool("completed", t.taskItems[index].Completed))
+	t.store.Checkpoint(i18n.T("todo.bulk.note"))
+	t.store.Update("todo-list", func(tasks []Task) []Task {
+		tasks[index].Note = note
+		return tasks
+	})
+	t.closeNote()
+	t.updateTasksDisplay(0)
+	t.logger.Debug("Task note edited", slog.String("task", item.Title), slog.Int("length", len(note)))
+
+	t.scheduleSave()
+}
+
+// cancelNote 关闭编辑器, 有未保存的修改时先确认
+func (t *TodoList) cancelNote() {
+	if t.note == nil {
+		return
+	}
+	if !t.note.Modified() {
+		t.closeNote()
+		return
+	}
+	t.app.Confirm(i18n.T("note.discard_title"), i18n.T("note.discard_message"), i18n.T("note.discard_ok"), t.closeNote)
+}
 
-	t.scheduleSave(t.savePath)
+func (t *TodoList) closeNote() {
+	t.note = nil
+	t.body.SwitchToPage("tasks")
+	t.body.RemovePage("note")
+	t.app.SetFocus(t.tasks)
 }
 
 func (t *TodoList) configureHandlers() {
 	t.input.SetDoneFunc(t.handleInputDone)
 	t.input.SetChangedFunc(t.handleInputText)
 	t.tasks.SetInputCapture(t.handleListInput)
+	t.tasks.SetChangedFunc(func(int, string, string, rune) {
+		// 范围选择随当前行变化
+		if t.selection.anchor >= 0 {
+			t.refreshRows()
+		}
+	})
 }
 
+// handleInputText 编辑时限制标题宽度. 新建任务时快速添加标记不算在标题内,
+// 输入中的标记可能还不完整, 所以在添加时检查解析出的标题
 func (t *TodoList) handleInputText(text string) {
-	if len(text) > 80 {
-		t.input.SetText(text[:80])
-		t.updateHint("Task length should not exceed 80 characters.")
+	if !t.editMode {
+		t.updatePreview(text)
+		return
+	}
+	if title, truncated := task.TruncateTitle(text, t.cfg.TitleLimit); truncated {
+		t.input.SetText(title)
+		t.app.Notify(ui.SeverityWarning, i18n.T("todo.too_long", t.cfg.TitleLimit))
+		return
 	}
+	t.updatePreview(text)
 }
 
 func (t *TodoList) handleInputDone(key tcell.Key) {
@@ -301,107 +833,29 @@ func (t *TodoList) handleInputDone(key tcell.Key) {
 		}
 	} else if key == tcell.KeyEsc {
 		t.CancelEdit()
+	} else if key == tcell.KeyTab {
+		t.app.SetFocus(t.tasks)
 	}
 }
 
 func (t *TodoList) handleListInput(event *tcell.EventKey) *tcell.EventKey {
-	switch event.Key() {
-	case tcell.KeyRune:
-		switch event.Rune() {
-		case ' ':
-			t.CompleteTask()
-			return nil
-		default:
-			return event
-		}
-	case tcell.KeyDelete:
-		t.DeleteTask()
-		return nil
-	case tcell.KeyEnter:
-		if t.editMode {
-			t.SaveEdit()
-		} else {
-			t.EditTask()
-		}
-		return nil
-	case tcell.KeyEsc:
-		t.CancelEdit()
-		return nil
-	default:
-		// t.logger.Debug("Unhandled key", slog.String("key", event.Name()), slog.Int("key", int(event.Key())))
-		return event
-	}
+	return t.app.Keymap.Dispatch("todo-list", event)
 }
 
 func (t *TodoList) setupLayout() {
 	t.SetDirection(tview.FlexRow).
 		AddItem(t.input, 1, 1, true).
-		AddItem(t.hint, 1, 1, false).
-		AddItem(t.tasks, 0, 1, false)
+		AddItem(t.preview, 0, 0, false).
+		AddItem(t.body, 0, 1, false)
+
+	t.body.AddPage("tasks", t.tasks, true, true)
+	t.preview.SetDynamicColors(true)
 
 	t.SetBorder(true).
-		SetTitle("To-Do List").
 		SetTitleAlign(tview.AlignCenter)
+	t.updateTitle()
 }
 
-func (t *TodoList) loadTasks(savePath string) error {
-	t.mutex.Lock()
-	defer t.mutex.Unlock()
-
-	data, err := os.ReadFile(savePath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			t.logger.Debug("No tasks found", slog.String("savePath", savePath))
-			t.taskItems = []Task{}
-			return nil
-		}
-		return err
-	}
-
-	err = json.Unmarshal(data, &t.taskItems)
-	if err != nil {
-		return err
-	}
-
-	t.logger.Info("Tasks loaded", slog.String("savePath", savePath))
-	return nil
-}
-
-func (t *TodoList) saveTasks(savePath string) error {
-	t.mutex.Lock()
-	defer t.mutex.Unlock()
-
-	data, err := json.Marshal(t.taskItems)
-	if err != nil {
-		return err
-	}
-
-	dir := filepath.Dir(savePath)
-	err = os.MkdirAll(dir, 0755)
-	if err != nil {
-		return err
-	}
-
-	err = os.WriteFile(savePath, data, 0644)
-	if err != nil {
-		return err
-	}
-
-	t.logger.Info("Tasks saved", slog.String("savePath", savePath))
-	return nil
-}
-
-func (t *TodoList) scheduleSave(savePath string) {
-	if t.saveTimer != nil {
-		t.saveTimer.Stop()
-	}
-
-	t.saveTimer = time.AfterFunc(1*time.Second, func() {
-		err := t.saveTasks(savePath)
-		if err != nil {
-			t.logger.Error("Failed to save tasks", slog.String("error", err.Error()))
-		}
-
-		t.updateHint("Tasks saved to file:" + savePath)
-	})
+func (t *TodoList) scheduleSave() {
+	scheduleSave(t.logger, t.app, t.store)
 }
@@ -0,0 +1,173 @@
+package view_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"kongtools/internal/i18n"
+	"kongtools/internal/task"
+	"kongtools/internal/uitest"
+	"kongtools/internal/view"
+)
+
+// newTodoList 在只启用待办列表的应用中打开有titles的任务文件, 焦点在输入框
+func newTodoList(t *testing.T, titles ...string) *uitest.Harness {
+	t.Helper()
+	h := uitest.New(t, view.Config{TasksSavePath: writeTasks(t, titles...), Tools: []string{"todo-list"}})
+	h.Press("F3")
+	return h
+}
+
+// writeTasks 在临时目录写入有titles的任务文件, 返回文件路径
+func writeTasks(t *testing.T, titles ...string) string {
+	t.Helper()
+	created := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	tasks := make([]task.Task, len(titles))
+	for i, title := range titles {
+		tasks[i] = task.Task{Title: title, CreatedAt: created}
+	}
+	data, err := json.Marshal(tasks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// titles 保存后任务文件中的标题和完成状态
+func titles(t *testing.T, h *uitest.Harness) []string {
+	t.Helper()
+	if err := h.App.Store.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	tasks, err := task.Load(h.App.Store.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := make([]string, len(tasks))
+	for i, item := range tasks {
+		result[i] = item.Title
+		if item.Completed {
+			result[i] = "[x] " + item.Title
+		}
+	}
+	return result
+}
+
+func assertTitles(t *testing.T, h *uitest.Harness, want ...string) {
+	t.Helper()
+	if got := titles(t, h); !slices.Equal(got, want) {
+		t.Fatalf("tasks = %q, want %q", got, want)
+	}
+}
+
+func TestTodoListAdd(t *testing.T) {
+	h := newTodoList(t, "buy milk")
+	h.Type("write report")
+	h.Press("Enter")
+	h.WaitForText("write report")
+
+	assertTitles(t, h, "buy milk", "write report")
+	h.AssertGolden("todo_add")
+}
+
+func TestTodoListChinese(t *testing.T) {
+	h := uitest.New(t, view.Config{TasksSavePath: writeTasks(t, "买牛奶"), Tools: []string{"todo-list"}, Language: i18n.Chinese})
+	h.Press("F3")
+	h.Type("写报告")
+	h.Press("Enter")
+	h.WaitForText("写报告")
+
+	assertTitles(t, h, "买牛奶", "写报告")
+	h.AssertGolden("todo_zh")
+}
+
+func TestTodoListTitleLimit(t *testing.T) {
+	h := uitest.New(t, view.Config{TasksSavePath: writeTasks(t, "call mom"), Tools: []string{"todo-list"}, Todo: view.TodoConfig{TitleLimit: 10}})
+	h.Press("F3")
+	// 标记不算在标题内
+	h.Type("buy milk #home !high @tomorrow 18:00 every week")
+	h.Press("Enter")
+	h.WaitForText("buy milk")
+
+	// 标题超出时不添加, 保留输入以便修改
+	h.Type("abcdefghijk #home")
+	h.Press("Enter")
+	h.WaitForText("Task title should not exceed 10 columns")
+	h.Press("Home", "Delete", "Enter")
+	h.WaitForText("bcdefghijk")
+
+	assertTitles(t, h, "call mom", "buy milk", "bcdefghijk")
+	for _, item := range h.App.Store.Tasks()[1:] {
+		if len(item.Tags) != 1 || item.Tags[0] != "home" {
+			t.Errorf("tags of %q = %q, want [home]", item.Title, item.Tags)
+		}
+	}
+}
+
+func TestTodoListEdit(t *testing.T) {
+	h := newTodoList(t, "buy milk", "write report")
+	h.Press("Tab", "Down", "Enter")
+	h.WaitForText("Edit To-Do:")
+	h.Press("Tab", "Ctrl+U")
+	h.Type("write the report")
+	h.Press("Enter")
+	h.WaitForText("write the report")
+
+	assertTitles(t, h, "buy milk", "write the report")
+	h.AssertGolden("todo_edit")
+}
+
+func TestTodoListEditCancel(t *testing.T) {
+	h := newTodoList(t, "buy milk")
+	h.Press("Tab", "Enter")
+	h.WaitForText("Edit To-Do:")
+	h.Press("Tab")
+	h.Type(" now")
+	h.Press("Esc")
+	h.WaitForText("New To-Do:")
+
+	assertTitles(t, h, "buy milk")
+}
+
+func TestTodoListDelete(t *testing.T) {
+	h := newTodoList(t, "buy milk", "write report")
+	h.Press("Tab", "Delete")
+	h.WaitForText("Delete")
+	h.AssertGolden("todo_delete_confirm")
+
+	h.Press("Enter")
+	h.WaitForNoText("buy milk")
+	assertTitles(t, h, "write report")
+	h.AssertGolden("todo_delete")
+}
+
+func TestTodoListDeleteCancel(t *testing.T) {
+	h := newTodoList(t, "buy milk")
+	h.Press("Tab", "Delete")
+	h.WaitForText("Delete")
+	h.Press("Esc")
+	h.Settle()
+
+	assertTitles(t, h, "buy milk")
+}
+
+func TestTodoListToggle(t *testing.T) {
+	h := newTodoList(t, "buy milk", "write report")
+	h.Press("Tab", "Space")
+	h.WaitForText("[x]")
+
+	assertTitles(t, h, "[x] buy milk", "write report")
+	h.AssertGolden("todo_toggle")
+
+	h.Press("Space")
+	h.WaitForNoText("[x]")
+	assertTitles(t, h, "buy milk", "write report")
+}
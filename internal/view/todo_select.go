@@ -0,0 +1,339 @@
+package view
+
+import (
+	"errors"
+	"kongtools/internal/i18n"
+	"kongtools/internal/task"
+	"kongtools/internal/ui"
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+// taskSelection 多选的任务
+type taskSelection struct {
+	marked map[int]bool // 标记的任务序号
+	anchor int          // 范围选择起点所在的行, -1表示不在范围选择中
+}
+
+func newTaskSelection() taskSelection {
+	return taskSelection{marked: map[int]bool{}, anchor: -1}
+}
+
+// active 是否有标记或在范围选择中
+func (s taskSelection) active() bool {
+	return len(s.marked) > 0 || s.anchor >= 0
+}
+
+// inRange 第row行是否在起点和当前行之间
+func (s taskSelection) inRange(row, current int) bool {
+	if s.anchor < 0 {
+		return false
+	}
+	return row >= min(s.anchor, current) && row <= max(s.anchor, current)
+}
+
+// selected 选中的任务序号, 从小到大
+func (t *TodoList) selected() []int {
+	current := t.tasks.GetCurrentItem()
+	var indexes []int
+	for row, index := range t.visible {
+		if t.selection.marked[index] || t.selection.inRange(row, current) {
+			indexes = append(indexes, index)
+		}
+	}
+	return indexes
+}
+
+// bulkTargets 批量操作的任务序号, 没有选中时为当前任务
+func (t *TodoList) bulkTargets() []int {
+	if indexes := t.selected(); len(indexes) > 0 {
+		return indexes
+	}
+	if index, ok := t.currentIndex(); ok {
+		return []int{index}
+	}
+	return nil
+}
+
+// rowText 第row行的显示文本, 多选时在前面显示标记
+func (t *TodoList) rowText(row int, item Task) string {
+	text := taskText(item, t.theme)
+	if !t.selection.active() {
+		return text
+	}
+	if row < len(t.visible) && (t.selection.marked[t.visible[row]] || t.selection.inRange(row, t.tasks.GetCurrentItem())) {
+		return ui.Tag(t.theme.Accent) + "● [-]" + text
+	}
+	return "  " + text
+}
+
+// refreshRows 只刷新每行的文本, 不增删行, 可以在列表的changed回调中调用
+func (t *TodoList) refreshRows() {
+	items := t.store.Tasks()
+	for row, index := range t.visible {
+		if row >= t.tasks.GetItemCount() || index >= len(items) {
+			break
+		}
+		t.tasks.SetItemText(row, t.rowText(row, items[index]), "")
+	}
+	t.updateTitle()
+}
+
+// ToggleMark 标记或取消标记当前任务, 然后移到下一行
+func (t *TodoList) ToggleMark() {
+	index, ok := t.currentIndex()
+	if !ok {
+		return
+	}
+	if t.selection.marked[index] {
+		delete(t.selection.marked, index)
+	} else {
+		t.selection.marked[index] = true
+	}
+	if current := t.tasks.GetCurrentItem(); current < t.tasks.GetItemCount()-1 {
+		t.tasks.SetCurrentItem(current + 1)
+	}
+	t.refreshRows()
+}
+
+// ToggleRange 开始范围选择, 再次调用时把范围内的任务加入标记
+func (t *TodoList) ToggleRange() {
+	if t.tasks.GetItemCount() == 0 {
+		return
+	}
+	if t.selection.anchor < 0 {
+		t.selection.anchor = t.tasks.GetCurrentItem()
+	} else {
+		for _, index := range t.selected() {
+			t.selection.marked[index] = true
+		}
+		t.selection.anchor = -1
+	}
+	t.refreshRows()
+}
+
+// SelectAll 标记当前过滤条件下的所有任务, 已全部标记时取消
+func (t *TodoList) SelectAll() {
+	all := len(t.visible) > 0
+	for _, index := range t.visible {
+		if !t.selection.marked[index] {
+			all = false
+			break
+		}
+	}
+	t.selection = newTaskSelection()
+	if !all {
+		for _, index := range t.visible {
+			t.selection.marked[index] = true
+		}
+	}
+	t.refreshRows()
+}
+
+// ClearSelection 取消所有标记, 返回是否有标记被取消
+func (t *TodoList) ClearSelection() bool {
+	if !t.selection.active() {
+		return false
+	}
+	t.selection = newTaskSelection()
+	t.refreshRows()
+	return true
+}
+
+// finishBulk 批量修改后清除选择, 刷新并保存一次
+func (t *TodoList) finishBulk(label string, count int) {
+	current := t.tasks.GetCurrentItem()
+	t.selection = newTaskSelection()
+	t.updateTasksDisplay(0)
+	t.tasks.SetCurrentItem(current)
+	t.updateTitle()
+	t.logger.Debug("Bulk operation", slog.String("operation", label), slog.Int("count", count))
+	t.app.Notify(ui.SeveritySuccess, i18n.T("todo.bulk_done", label, count, t.app.Keymap.Hint("todo-list", "undo")))
+
+	t.scheduleSave()
+}
+
+// completeSelected 完成选中的任务, 都已完成时重新打开
+func (t *TodoList) completeSelected() {
+	indexes := t.bulkTargets()
+	if len(indexes) == 0 {
+		return
+	}
+	items := t.store.Tasks()
+	completed := false
+	for _, index := range indexes {
+		if !items[index].Completed {
+			completed = true
+			break
+		}
+	}
+
+	label := i18n.T("todo.bulk.reopen")
+	action := task.ActionReopened
+	if completed {
+		label = i18n.T("todo.bulk.complete")
+		action = task.ActionCompleted
+	}
+	var changed []Task
+	t.store.Checkpoint(label)
+	t.store.Update("todo-list", func(tasks []Task) []Task {
+		for _, index := range indexes {
+			if tasks[index].Completed != completed {
+				t.workflow.SetCompleted(&tasks[index], completed)
+				changed = append(changed, tasks[index])
+			}
+		}
+		return tasks
+	})
+	for _, item := range changed {
+		recordTask(t.logger, t.store, action, item)
+	}
+	t.finishBulk(label, len(changed))
+}
+
+// deleteSelected 确认后删除选中的任务
+func (t *TodoList) deleteSelected() {
+	indexes := t.selected()
+	if len(indexes) == 0 {
+		return
+	}
+	items := t.store.Tasks()
+	targets := make([]Task, len(indexes))
+	for i, index := range indexes {
+		targets[i] = items[index]
+	}
+
+	t.app.Confirm(i18n.T("todo.delete_title"), i18n.T("todo.bulk_delete_message", len(indexes)), i18n.T("todo.delete_ok"), func() {
+		// 期间任务被其他视图修改时放弃
+		for i, index := range indexes {
+			current, ok := t.store.Get(index)
+			if !ok || current.Title != targets[i].Title || !current.CreatedAt.Equal(targets[i].CreatedAt) {
+				t.app.Notify(ui.SeverityWarning, i18n.T("todo.changed"))
+				return
+			}
+		}
+
+		label := i18n.T("todo.bulk.delete")
+		deleted := map[int]bool{}
+		for _, index := range indexes {
+			deleted[index] = true
+		}
+		t.store.Checkpoint(label)
+		t.store.Update("todo-list", func(tasks []Task) []Task {
+			kept := tasks[:0]
+			for i, item := range tasks {
+				if !deleted[i] {
+					kept = append(kept, item)
+				}
+			}
+			return kept
+		})
+		for _, item := range targets {
+			recordTask(t.logger, t.store, task.ActionDeleted, item)
+		}
+		t.finishBulk(label, len(targets))
+	})
+}
+
+// TagSelected 为选中的任务添加或删除标签, 没有选中时为当前任务
+func (t *TodoList) TagSelected() {
+	if len(t.bulkTargets()) == 0 {
+		return
+	}
+	t.app.Prompt(i18n.T("todo.tag_title"), i18n.T("todo.tag_label"), "", func(text string) error {
+		var add, remove []string
+		for _, field := range strings.FieldsFunc(text, func(r rune) bool { return r == ' ' || r == ',' }) {
+			if tag, ok := strings.CutPrefix(field, "-"); ok {
+				remove = append(remove, strings.TrimPrefix(tag, "#"))
+			} else {
+				add = append(add, strings.TrimPrefix(strings.TrimPrefix(field, "+"), "#"))
+			}
+		}
+		if len(add) == 0 && len(remove) == 0 {
+			return errors.New(i18n.T("todo.tag_empty"))
+		}
+
+		indexes := t.bulkTargets()
+		label := i18n.T("todo.bulk.tag")
+		t.store.Checkpoint(label)
+		t.store.Update("todo-list", func(tasks []Task) []Task {
+			for _, index := range indexes {
+				tasks[index].Tags = editTags(tasks[index].Tags, add, remove)
+			}
+			return tasks
+		})
+		t.finishBulk(label, len(indexes))
+		return nil
+	})
+}
+
+// editTags 添加add中没有的标签, 删除remove中的标签
+func editTags(tags, add, remove []string) []string {
+	result := make([]string, 0, len(tags)+len(add))
+	for _, tag := range append(append([]string{}, tags...), add...) {
+		if tag == "" || slices.Contains(result, tag) || slices.Contains(remove, tag) {
+			continue
+		}
+		result = append(result, tag)
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+// MoveSelected 把选中的任务移到看板的另一个状态, 没有选中时为当前任务
+func (t *TodoList) MoveSelected() {
+	indexes := t.bulkTargets()
+	if len(indexes) == 0 {
+		return
+	}
+	item, _ := t.store.Get(indexes[0])
+	statuses := strings.Join(t.workflow.Statuses, ", ")
+	t.app.Prompt(i18n.T("todo.move_title"), i18n.T("todo.move_label", statuses), t.workflow.Status(item), func(text string) error {
+		status := strings.TrimSpace(text)
+		if t.workflow.Index(status) < 0 {
+			return errors.New(i18n.T("todo.invalid_status", statuses))
+		}
+
+		indexes := t.bulkTargets()
+		label := i18n.T("todo.bulk.move", status)
+		var changed []Task
+		t.store.Checkpoint(label)
+		t.store.Update("todo-list", func(tasks []Task) []Task {
+			for _, index := range indexes {
+				if t.workflow.Status(tasks[index]) != status {
+					t.workflow.SetStatus(&tasks[index], status)
+					changed = append(changed, tasks[index])
+				}
+			}
+			return tasks
+		})
+		for _, item := range changed {
+			recordTask(t.logger, t.store, task.ActionMoved, item)
+		}
+		t.finishBulk(label, len(changed))
+		return nil
+	})
+}
+
+// Undo 撤销最近一次修改
+func (t *TodoList) Undo() {
+	t.CancelEdit()
+	current := t.tasks.GetCurrentItem()
+	// Undo会通知所有订阅者, 包括本页面
+	label, ok, err := t.store.Undo()
+	if err != nil {
+		t.logger.Error("Failed to record undo", slog.String("error", err.Error()))
+	}
+	if !ok {
+		t.app.Notify(ui.SeverityInfo, i18n.T("todo.nothing_to_undo"))
+		return
+	}
+	t.tasks.SetCurrentItem(current)
+	t.logger.Debug("Undo", slog.String("operation", label))
+	t.app.Notify(ui.SeverityInfo, i18n.T("todo.undone", label))
+
+	t.scheduleSave()
+}
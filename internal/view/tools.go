@@ -0,0 +1,102 @@
+package view
+
+import (
+	"fmt"
+	"io"
+	"kongtools/internal/task"
+	"kongtools/internal/ui"
+	"log/slog"
+	"sort"
+)
+
+// toolFactory 创建工具页面
+type toolFactory func(a *App, logger *slog.Logger) ui.Page
+
+var (
+	// tools 各工具在init中注册自己
+	tools = map[string]toolFactory{}
+	// defaultTools 未配置app.tools时启用的工具及顺序
+	defaultTools = []string{"welcome", "todo-list", "board", "calendar", "stats", "keys"}
+)
+
+// registerTool 注册工具, 在各工具文件的init中调用
+func registerTool(name string, factory toolFactory) {
+	if _, ok := tools[name]; ok {
+		panic(fmt.Sprintf("tool %q already registered", name))
+	}
+	tools[name] = factory
+}
+
+// ToolNames 所有工具的名称, 按名称排序
+func ToolNames() []string {
+	names := make([]string, 0, len(tools))
+	for name := range tools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// KeyActions 启用所有工具时可以配置按键的动作, 作用域 -> 动作名称.
+// 在不运行的应用中初始化所有工具得到, 用于检查按键配置
+func KeyActions() (map[string][]string, error) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	a := &App{
+		App:        ui.NewApp(logger),
+		Store:      task.NewStore(""),
+		workflow:   task.NewWorkflow(nil),
+		cfg:        Config{Tools: ToolNames()},
+		baseLogger: logger,
+		logger:     logger,
+	}
+	defer a.Background.Shutdown()
+	defer a.ShutdownPages()
+
+	a.App.Init()
+	if err := a.registerTools(); err != nil {
+		return nil, err
+	}
+	if err := a.InitActions(); err != nil {
+		return nil, err
+	}
+	actions := map[string][]string{}
+	for _, action := range a.Keymap.Actions() {
+		actions[action.Scope] = append(actions[action.Scope], action.Name)
+	}
+	return actions, nil
+}
+
+// registerTools 按配置创建工具页面并注册到ui
+func (a *App) registerTools() error {
+	pages, err := a.toolPages(a.cfg.Tools)
+	if err != nil {
+		return err
+	}
+	for _, p := range pages {
+		if err := a.Registry.Register(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// toolPages 按names的顺序返回启用的工具页面, 已注册的页面不重新创建
+func (a *App) toolPages(names []string) ([]ui.Page, error) {
+	if len(names) == 0 {
+		names = defaultTools
+	}
+
+	var pages []ui.Page
+	for _, name := range names {
+		if p, ok := a.Registry.Page(name); ok {
+			pages = append(pages, p)
+			continue
+		}
+		factory, ok := tools[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown tool %q in app.tools", name)
+		}
+		pages = append(pages, factory(a, a.baseLogger))
+	}
+	return pages, nil
+}
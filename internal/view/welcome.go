@@ -1,7 +1,13 @@
 package view
 
 import (
+	"kongtools/internal/i18n"
+	"kongtools/internal/task"
+	"kongtools/internal/ui"
 	"log/slog"
+	"math/rand"
+	"strings"
+	"time"
 
 	"github.com/rivo/tview"
 )
@@ -19,17 +25,81 @@ var WelcomeMsg = []string{
 	`╚═╝  ╚═╝ ╚═════╝ ╚═╝  ╚═══╝ ╚═════╝        ╚═╝    ╚═════╝  ╚═════╝ ╚══════╝╚══════╝`,
 }
 
+// 欢迎页的区块
+const (
+	SectionBanner = "banner" // 标题图案
+	SectionClock  = "clock"  // 日期时间
+	SectionToday  = "today"  // 今天到期和已过期的任务
+	SectionTip    = "tip"    // 随机按键提示
+	SectionRecent = "recent" // 最近使用的工具
+)
+
+// WelcomeSections 所有的区块
+var WelcomeSections = []string{SectionBanner, SectionClock, SectionToday, SectionTip, SectionRecent}
+
+// WelcomeConfig 欢迎页配置
+type WelcomeConfig struct {
+	Sections        []string
+	RefreshInterval time.Duration
+}
+
+// welcomeTasksLimit 每类任务最多显示的数量
+const welcomeTasksLimit = 5
+
+// tipInterval 更换提示的间隔
+const tipInterval = 30 * time.Second
+
+// welcomeTips 按键提示的键
+var welcomeTips = []string{
+	"welcome.tip.palette",
+	"welcome.tip.todo",
+	"welcome.tip.complete",
+	"welcome.tip.timer",
+	"welcome.tip.due",
+	"welcome.tip.entries",
+	"welcome.tip.board",
+	"welcome.tip.calendar_week",
+	"welcome.tip.calendar_open",
+	"welcome.tip.report",
+}
+
+func init() {
+	registerTool("welcome", func(a *App, logger *slog.Logger) ui.Page {
+		return NewWelcome(logger, a.App, a.cfg.Welcome, a.Store, a.recentTitles)
+	})
+}
+
 // Welcome 欢迎页
 type Welcome struct {
 	*tview.TextView
 
+	cfg    WelcomeConfig
+	store  *task.Store
+	recent func() []string
+
+	tip    string
+	tipAt  time.Time
+	stop   func() // 停止定时刷新
+	app    *ui.App
+	theme  *ui.Theme
 	logger *slog.Logger
 }
 
-// NewWelcome 新建
-func NewWelcome(logger *slog.Logger) *Welcome {
+// NewWelcome 新建, recent返回最近使用的工具
+func NewWelcome(logger *slog.Logger, app *ui.App, cfg WelcomeConfig, store *task.Store, recent func() []string) *Welcome {
+	if len(cfg.Sections) == 0 {
+		cfg.Sections = []string{SectionBanner}
+	}
+	if cfg.RefreshInterval <= 0 {
+		cfg.RefreshInterval = time.Second
+	}
+
 	w := Welcome{
 		TextView: tview.NewTextView(),
+		cfg:      cfg,
+		store:    store,
+		recent:   recent,
+		app:      app,
 		logger:   logger.With("module", "view-welcome"),
 	}
 
@@ -38,17 +108,135 @@ func NewWelcome(logger *slog.Logger) *Welcome {
 	w.SetDynamicColors(true)
 	w.SetWordWrap(true)
 	w.SetWrap(false)
-	w.SetTitle("Welcome to KongTools")
-	w.refreshWelcome()
+	w.SetTitle(i18n.T("welcome.title"))
+	app.OnThemeChange(w.Info().Name, func(theme *ui.Theme) {
+		w.theme = theme
+		theme.ApplyTextView(w.TextView)
+		w.refreshWelcome()
+	})
 
 	return &w
 }
 
+// Info 页面信息
+func (w *Welcome) Info() ui.PageInfo {
+	return ui.PageInfo{Name: "welcome", Title: i18n.T("welcome.name"), Description: i18n.T("welcome.description"), Shortcut: 'w'}
+}
+
+// Primitive 页面内容
+func (w *Welcome) Primitive() tview.Primitive {
+	return w
+}
+
+// Init 开始定时刷新
+func (w *Welcome) Init() error {
+	w.Start()
+	return nil
+}
+
+// OnFocus 立即刷新
+func (w *Welcome) OnFocus() {
+	w.refreshWelcome()
+}
+
+// Shutdown 停止定时刷新
+func (w *Welcome) Shutdown() {
+	w.Stop()
+}
+
+// Start 开始定时刷新
+func (w *Welcome) Start() {
+	if w.stop != nil {
+		return
+	}
+	w.stop = w.app.Background.Every(w.cfg.RefreshInterval, w.refreshWelcome)
+}
+
+// Stop 停止定时刷新
+func (w *Welcome) Stop() {
+	if w.stop != nil {
+		w.stop()
+		w.stop = nil
+	}
+}
+
 func (w *Welcome) refreshWelcome() {
-	w.logger.Debug("refresh welcome ...")
 	w.Clear()
 
-	for _, line := range WelcomeMsg {
-		w.Write([]byte(line + "\n"))
+	now := time.Now()
+	for _, section := range w.cfg.Sections {
+		var lines []string
+		switch section {
+		case SectionBanner:
+			lines = WelcomeMsg
+		case SectionClock:
+			lines = []string{"", "[::b]" + i18n.T("welcome.clock", i18n.Weekday(now.Weekday()), now.Format(time.DateTime)) + "[::-]"}
+		case SectionToday:
+			lines = w.todayLines(now)
+		case SectionTip:
+			lines = w.tipLines(now)
+		case SectionRecent:
+			lines = w.recentLines()
+		default:
+			lines = []string{"", ui.Tag(w.theme.Error) + tview.Escape(i18n.T("welcome.unknown_section", section)) + "[-]"}
+		}
+
+		for _, line := range lines {
+			w.Write([]byte(line + "\n"))
+		}
+	}
+}
+
+func (w *Welcome) todayLines(now time.Time) []string {
+	var due, overdue []string
+	for _, item := range w.store.Tasks() {
+		switch {
+		case item.Overdue(now):
+			overdue = append(overdue, item.Title)
+		case !item.Completed && item.DueOn(now):
+			due = append(due, item.Title)
+		}
+	}
+
+	lines := []string{""}
+	lines = append(lines, ui.Tag(w.theme.Accent)+i18n.T("welcome.due_today", len(due))+"[-]")
+	lines = append(lines, w.limitLines(due, "")...)
+	if len(overdue) > 0 {
+		lines = append(lines, ui.Tag(w.theme.Error)+i18n.T("welcome.overdue", len(overdue))+"[-]")
+		lines = append(lines, w.limitLines(overdue, ui.Tag(w.theme.Error))...)
+	}
+	return lines
+}
+
+// limitLines 转义并最多保留welcomeTasksLimit行
+func (w *Welcome) limitLines(titles []string, color string) []string {
+	var lines []string
+	for i, title := range titles {
+		if i == welcomeTasksLimit {
+			lines = append(lines, ui.Tag(w.theme.Secondary)+i18n.T("welcome.more", len(titles)-i)+"[-]")
+			break
+		}
+		line := tview.Escape(title)
+		if color != "" {
+			line = color + line + "[-]"
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func (w *Welcome) tipLines(now time.Time) []string {
+	if w.tip == "" || now.Sub(w.tipAt) >= tipInterval {
+		w.tip = welcomeTips[rand.Intn(len(welcomeTips))]
+		w.tipAt = now
+	}
+	return []string{"", ui.Tag(w.theme.Secondary) + "💡 " + tview.Escape(i18n.T("welcome.tip", i18n.T(w.tip))) + "[-]"}
+}
+
+func (w *Welcome) recentLines() []string {
+	recent := w.recent()
+	if len(recent) == 0 {
+		return nil
 	}
+	return []string{"", ui.Tag(w.theme.Secondary) + tview.Escape(i18n.T("welcome.recent", strings.Join(recent, i18n.T("list.separator")))) + "[-]"}
 }